@@ -0,0 +1,28 @@
+package trier
+
+import (
+	"context"
+	"time"
+)
+
+// Attempt contains metadata of execution of retriable function.
+type Attempt struct {
+	// Number is the number of the execution, starts from 1.
+	Number int
+	// Elapsed is the time elapsed since the first execution.
+	Elapsed time.Duration
+	// Delay is the delay before the execution, 0 for the first execution.
+	Delay time.Duration
+}
+
+type attemptKey struct{}
+
+func withAttempt(ctx context.Context, a Attempt) context.Context {
+	return context.WithValue(ctx, attemptKey{}, a)
+}
+
+// AttemptFromContext returns metadata of execution of retriable function stored in context by trier.
+func AttemptFromContext(ctx context.Context) (Attempt, bool) {
+	a, ok := ctx.Value(attemptKey{}).(Attempt)
+	return a, ok
+}
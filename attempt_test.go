@@ -0,0 +1,32 @@
+package trier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttemptFromContext(t *testing.T) {
+	_, ok := AttemptFromContext(context.Background())
+	require.False(t, ok)
+
+	var attempts []Attempt
+	tr := NewTrier(Linear(time.Millisecond*10), WithMaxRetries(2))
+	err := tr.Run(context.Background(), func(ctx context.Context) (bool, error) {
+		a, ok := AttemptFromContext(ctx)
+		require.True(t, ok)
+		attempts = append(attempts, a)
+		return false, nil
+	})
+	require.Error(t, err)
+	require.Equal(t, 3, len(attempts))
+	for i, a := range attempts {
+		require.Equal(t, i+1, a.Number)
+		require.Equal(t, time.Millisecond*10*time.Duration(i), a.Delay)
+	}
+	require.Equal(t, time.Duration(0), attempts[0].Elapsed)
+	require.True(t, attempts[1].Elapsed >= time.Millisecond*10)
+	require.True(t, attempts[2].Elapsed >= time.Millisecond*30)
+}
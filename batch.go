@@ -0,0 +1,99 @@
+package trier
+
+import (
+	"context"
+	"sync"
+)
+
+// BatchResult is the result of execution of retriable function for an item of batch.
+type BatchResult struct {
+	OK  bool
+	Err error
+}
+
+type batchOptions struct {
+	workers int
+	stop    bool
+}
+
+// BatchOption configures batch execution.
+type BatchOption func(*batchOptions)
+
+// BatchWorkers sets maximum number of items processed concurrently. The default is 1.
+func BatchWorkers(n int) BatchOption {
+	return func(o *batchOptions) {
+		o.workers = n
+	}
+}
+
+// BatchStopOnPermanent stops processing of all items after the first permanent error.
+func BatchStopOnPermanent() BatchOption {
+	return func(o *batchOptions) {
+		o.stop = true
+	}
+}
+
+// TryBatch executes retriable function for each item using Try with shared context,
+// returns results in order of items. Returns the first permanent error if processing is stopped
+// or the error of context if it is done, results of items which are not processed contain the error of context.
+func TryBatch[T any](ctx context.Context, t Trier, items []T, fn func(ctx context.Context, item T) (bool, error), options ...BatchOption) ([]BatchResult, error) {
+	o := batchOptions{workers: 1}
+	for _, opt := range options {
+		opt(&o)
+	}
+	if o.workers < 1 {
+		o.workers = 1
+	}
+	if o.workers > len(items) {
+		o.workers = len(items)
+	}
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make([]BatchResult, len(items))
+	indexes := make(chan int)
+	var once sync.Once
+	var stopErr error
+	var wg sync.WaitGroup
+	wg.Add(o.workers)
+	for w := 0; w < o.workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if err := ctx.Err(); err != nil {
+					results[i] = BatchResult{Err: err}
+					continue
+				}
+				item := items[i]
+				ok, err := t.Try(ctx, func(ctx context.Context) (bool, error) {
+					return fn(ctx, item)
+				})
+				results[i] = BatchResult{ok, err}
+				if o.stop && IsPermanent(err) {
+					once.Do(func() {
+						stopErr = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+	n := 0
+loop:
+	for ; n < len(items); n++ {
+		select {
+		case indexes <- n:
+		case <-ctx.Done():
+			break loop
+		}
+	}
+	close(indexes)
+	wg.Wait()
+	for ; n < len(items); n++ {
+		results[n] = BatchResult{Err: ctx.Err()}
+	}
+	if stopErr != nil {
+		return results, stopErr
+	}
+	return results, parent.Err()
+}
@@ -0,0 +1,77 @@
+package trier
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTryBatch(t *testing.T) {
+	tr := NewTrier(Constant(time.Millisecond), WithMaxRetries(2))
+	e := errors.New("some error")
+	var running, max int32
+	results, err := TryBatch(context.Background(), tr, []int{0, 1, 2, 3, 4, 5}, func(ctx context.Context, item int) (bool, error) {
+		r := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&max)
+			if r <= m || atomic.CompareAndSwapInt32(&max, m, r) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		switch item {
+		case 1:
+			return false, e
+		case 2:
+			return false, nil
+		}
+		return true, nil
+	}, BatchWorkers(2))
+	require.NoError(t, err)
+	require.Equal(t, []BatchResult{{OK: true}, {Err: e}, {}, {OK: true}, {OK: true}, {OK: true}}, results)
+	require.True(t, atomic.LoadInt32(&max) <= 2)
+
+	results, err = TryBatch(context.Background(), tr, []string{}, func(ctx context.Context, item string) (bool, error) {
+		return true, nil
+	}, BatchWorkers(0))
+	require.NoError(t, err)
+	require.Equal(t, []BatchResult{}, results)
+}
+
+func TestTryBatchStopOnPermanent(t *testing.T) {
+	tr := NewTrier(Constant(time.Hour))
+	e := Permanent(errors.New("some error"))
+	results, err := TryBatch(context.Background(), tr, []int{0, 1, 2, 3}, func(ctx context.Context, item int) (bool, error) {
+		switch item {
+		case 0:
+			<-ctx.Done()
+			return false, ctx.Err()
+		case 1:
+			return false, e
+		}
+		return true, nil
+	}, BatchWorkers(2), BatchStopOnPermanent())
+	require.Equal(t, e, err)
+	require.Equal(t, []BatchResult{
+		{Err: context.Canceled},
+		{Err: e},
+		{Err: context.Canceled},
+		{Err: context.Canceled},
+	}, results)
+}
+
+func TestTryBatchContext(t *testing.T) {
+	tr := NewTrier(Constant(time.Hour))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+	results, err := TryBatch(ctx, tr, []int{0, 1}, func(ctx context.Context, item int) (bool, error) {
+		return false, nil
+	})
+	require.Equal(t, context.DeadlineExceeded, err)
+	require.Equal(t, []BatchResult{{Err: context.DeadlineExceeded}, {Err: context.DeadlineExceeded}}, results)
+}
@@ -0,0 +1,255 @@
+package trier
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrBreakerOpen is the error returned when circuit breaker is open.
+var ErrBreakerOpen = errors.New("trier: circuit breaker is open")
+
+// BreakerState is the state of circuit breaker.
+type BreakerState int
+
+const (
+	// BreakerClosed allows executions.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen rejects executions until cool-down period ends.
+	BreakerOpen
+	// BreakerHalfOpen allows one probe execution, closes breaker on success, opens on failure.
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// Breaker is a circuit breaker which is safe for concurrent use by multiple goroutines,
+// execution which is not ok is a failure unless the error is permanent.
+type Breaker struct {
+	mu          sync.Mutex
+	clock       Clock
+	consecutive int
+	rate        float64
+	minRequests int
+	window      time.Duration
+	cooldown    time.Duration
+	onChange    func(from, to BreakerState)
+
+	state    BreakerState
+	changed  time.Time
+	failures int
+	requests int
+	failed   int
+	probing  bool
+	gen      uint64
+}
+
+// BreakerOption configures circuit breaker.
+type BreakerOption func(*Breaker)
+
+// BreakerConsecutiveFailures sets number of consecutive failures which opens breaker, 0 disables the threshold.
+// The default is 5.
+func BreakerConsecutiveFailures(n int) BreakerOption {
+	return func(b *Breaker) {
+		b.consecutive = n
+	}
+}
+
+// BreakerFailureRate sets ratio of failures to executions which opens breaker,
+// the ratio is checked if number of executions is at least minRequests.
+func BreakerFailureRate(rate float64, minRequests int) BreakerOption {
+	return func(b *Breaker) {
+		b.rate = rate
+		b.minRequests = minRequests
+	}
+}
+
+// BreakerWindow sets period after which counts of executions and failures of closed breaker are reset,
+// 0 resets the counts only when breaker closes. The default is 0.
+func BreakerWindow(d time.Duration) BreakerOption {
+	return func(b *Breaker) {
+		b.window = d
+	}
+}
+
+// BreakerCooldown sets period after which open breaker becomes half-open. The default is 10 seconds.
+func BreakerCooldown(d time.Duration) BreakerOption {
+	return func(b *Breaker) {
+		b.cooldown = d
+	}
+}
+
+// BreakerOnStateChange sets function which is called when state of breaker changes.
+func BreakerOnStateChange(fn func(from, to BreakerState)) BreakerOption {
+	return func(b *Breaker) {
+		b.onChange = fn
+	}
+}
+
+// BreakerClock sets clock used by breaker to measure time.
+func BreakerClock(c Clock) BreakerOption {
+	return func(b *Breaker) {
+		b.clock = c
+	}
+}
+
+// NewBreaker creates new closed circuit breaker.
+func NewBreaker(options ...BreakerOption) *Breaker {
+	b := &Breaker{clock: clock{}, consecutive: 5, cooldown: time.Second * 10}
+	for _, o := range options {
+		o(b)
+	}
+	b.changed = b.clock.Now()
+	return b
+}
+
+// State returns current state of breaker.
+func (b *Breaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := b.state
+	if s == BreakerOpen && b.clock.Now().Sub(b.changed) >= b.cooldown {
+		s = BreakerHalfOpen
+	}
+	return s
+}
+
+// Allow returns ErrBreakerOpen if breaker rejects execution, otherwise returns generation of breaker
+// which must be passed to Done or Release with result of execution.
+func (b *Breaker) Allow() (uint64, error) {
+	b.mu.Lock()
+	from := b.state
+	now := b.clock.Now()
+	switch b.state {
+	case BreakerClosed:
+		if b.window > 0 && now.Sub(b.changed) >= b.window {
+			b.reset(now)
+		}
+	case BreakerOpen:
+		if now.Sub(b.changed) < b.cooldown {
+			b.mu.Unlock()
+			return 0, ErrBreakerOpen
+		}
+		b.setState(BreakerHalfOpen, now)
+		b.probing = true
+	case BreakerHalfOpen:
+		if b.probing {
+			b.mu.Unlock()
+			return 0, ErrBreakerOpen
+		}
+		b.probing = true
+	}
+	gen := b.gen
+	to := b.state
+	b.mu.Unlock()
+	b.notify(from, to)
+	return gen, nil
+}
+
+// Done reports result of execution allowed by Allow, execution failed unless it is ok or the error is permanent.
+// Results of executions allowed before the last change of state are dropped.
+func (b *Breaker) Done(gen uint64, ok bool, err error) {
+	failure := !ok && !IsPermanent(err)
+	b.mu.Lock()
+	if gen != b.gen {
+		b.mu.Unlock()
+		return
+	}
+	from := b.state
+	now := b.clock.Now()
+	switch b.state {
+	case BreakerClosed:
+		b.requests++
+		if failure {
+			b.failures++
+			b.failed++
+			if (b.consecutive > 0 && b.failures >= b.consecutive) ||
+				(b.rate > 0 && b.requests >= b.minRequests && float64(b.failed) >= b.rate*float64(b.requests)) {
+				b.setState(BreakerOpen, now)
+			}
+		} else {
+			b.failures = 0
+		}
+	case BreakerHalfOpen:
+		b.probing = false
+		if failure {
+			b.setState(BreakerOpen, now)
+		} else {
+			b.setState(BreakerClosed, now)
+		}
+	}
+	to := b.state
+	b.mu.Unlock()
+	b.notify(from, to)
+}
+
+// Release reports that execution allowed by Allow was stopped by the caller,
+// its result is not counted and half-open breaker allows another probe.
+func (b *Breaker) Release(gen uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if gen == b.gen && b.state == BreakerHalfOpen {
+		b.probing = false
+	}
+}
+
+// Wrap creates retriable function which fails with permanent ErrBreakerOpen if breaker rejects execution,
+// execution is released if context is done when it finishes.
+func (b *Breaker) Wrap(fn Retriable) Retriable {
+	return func(ctx context.Context) (bool, error) {
+		gen, err := b.Allow()
+		if err != nil {
+			return false, Permanent(err)
+		}
+		ok, err := fn(ctx)
+		b.report(ctx, gen, ok, err)
+		return ok, err
+	}
+}
+
+// report reports result of execution, releases execution if context is done.
+func (b *Breaker) report(ctx context.Context, gen uint64, ok bool, err error) {
+	if !ok && ctx.Err() != nil {
+		b.Release(gen)
+		return
+	}
+	b.Done(gen, ok, err)
+}
+
+func (b *Breaker) setState(s BreakerState, now time.Time) {
+	b.state = s
+	b.gen++
+	b.reset(now)
+}
+
+func (b *Breaker) reset(now time.Time) {
+	b.changed = now
+	b.failures = 0
+	b.requests = 0
+	b.failed = 0
+}
+
+func (b *Breaker) notify(from, to BreakerState) {
+	if from != to && b.onChange != nil {
+		b.onChange(from, to)
+	}
+}
+
+// WithBreaker sets circuit breaker which is consulted before each execution of retriable function,
+// trier returns ErrBreakerOpen without execution if breaker rejects it.
+func WithBreaker(b *Breaker) Option {
+	return optionFunc(func(t *Trier) {
+		t.breaker = b
+	})
+}
@@ -0,0 +1,184 @@
+package trier_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/da440dil/go-trier"
+	"github.com/da440dil/go-trier/triertest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBreakerState(t *testing.T) {
+	require.Equal(t, "closed", BreakerClosed.String())
+	require.Equal(t, "open", BreakerOpen.String())
+	require.Equal(t, "half-open", BreakerHalfOpen.String())
+	require.Equal(t, "unknown", BreakerState(-1).String())
+}
+
+func TestBreakerConsecutiveFailures(t *testing.T) {
+	c := triertest.NewClock(time.Unix(0, 0))
+	var changes []string
+	b := NewBreaker(
+		BreakerConsecutiveFailures(2),
+		BreakerCooldown(time.Second),
+		BreakerClock(c),
+		BreakerOnStateChange(func(from, to BreakerState) {
+			changes = append(changes, from.String()+" -> "+to.String())
+		}),
+	)
+	e := errors.New("some error")
+
+	b.Done(allow(t, b), false, e)
+	b.Done(allow(t, b), true, nil)
+	b.Done(allow(t, b), false, Permanent(e))
+	b.Done(allow(t, b), false, e)
+	require.Equal(t, BreakerClosed, b.State())
+	b.Done(allow(t, b), false, e)
+	require.Equal(t, BreakerOpen, b.State())
+	_, err := b.Allow()
+	require.Equal(t, ErrBreakerOpen, err)
+
+	c.Advance(time.Second)
+	require.Equal(t, BreakerHalfOpen, b.State())
+	gen := allow(t, b)
+	_, err = b.Allow()
+	require.Equal(t, ErrBreakerOpen, err)
+	b.Done(gen, false, e)
+	require.Equal(t, BreakerOpen, b.State())
+
+	c.Advance(time.Second)
+	b.Done(allow(t, b), true, nil)
+	require.Equal(t, BreakerClosed, b.State())
+	require.Equal(t, []string{
+		"closed -> open",
+		"open -> half-open",
+		"half-open -> open",
+		"open -> half-open",
+		"half-open -> closed",
+	}, changes)
+}
+
+func allow(t *testing.T, b *Breaker) uint64 {
+	gen, err := b.Allow()
+	require.NoError(t, err)
+	return gen
+}
+
+func TestBreakerGeneration(t *testing.T) {
+	c := triertest.NewClock(time.Unix(0, 0))
+	b := NewBreaker(BreakerConsecutiveFailures(1), BreakerCooldown(time.Second), BreakerClock(c))
+	e := errors.New("some error")
+
+	late := allow(t, b)
+	b.Done(allow(t, b), false, e)
+	require.Equal(t, BreakerOpen, b.State())
+
+	c.Advance(time.Second)
+	probe := allow(t, b)
+	b.Done(late, true, nil)
+	require.Equal(t, BreakerHalfOpen, b.State())
+	_, err := b.Allow()
+	require.Equal(t, ErrBreakerOpen, err)
+
+	b.Release(late)
+	_, err = b.Allow()
+	require.Equal(t, ErrBreakerOpen, err)
+	b.Release(probe)
+	probe = allow(t, b)
+	b.Done(probe, false, e)
+	require.Equal(t, BreakerOpen, b.State())
+}
+
+func TestBreakerFailureRate(t *testing.T) {
+	c := triertest.NewClock(time.Unix(0, 0))
+	b := NewBreaker(
+		BreakerConsecutiveFailures(0),
+		BreakerFailureRate(0.5, 4),
+		BreakerWindow(time.Minute),
+		BreakerClock(c),
+	)
+	e := errors.New("some error")
+
+	for _, err := range []error{e, nil, e} {
+		b.Done(allow(t, b), err == nil, err)
+	}
+	require.Equal(t, BreakerClosed, b.State())
+
+	c.Advance(time.Minute)
+	for _, err := range []error{e, nil, e} {
+		b.Done(allow(t, b), err == nil, err)
+	}
+	require.Equal(t, BreakerClosed, b.State())
+	b.Done(allow(t, b), false, e)
+	require.Equal(t, BreakerOpen, b.State())
+}
+
+func TestBreakerWrap(t *testing.T) {
+	b := NewBreaker(BreakerConsecutiveFailures(1))
+	e := errors.New("some error")
+	n := 0
+	fn := b.Wrap(func(ctx context.Context) (bool, error) {
+		n++
+		return false, e
+	})
+	ok, err := fn(context.Background())
+	require.False(t, ok)
+	require.Equal(t, e, err)
+	ok, err = fn(context.Background())
+	require.False(t, ok)
+	require.True(t, IsPermanent(err))
+	require.True(t, errors.Is(err, ErrBreakerOpen))
+	require.Equal(t, 1, n)
+}
+
+func TestTrierWithBreaker(t *testing.T) {
+	b := NewBreaker(BreakerConsecutiveFailures(2))
+	tr := NewTrierWithOptions(Constant(time.Millisecond), WithBreaker(b))
+	e := errors.New("some error")
+	n := 0
+	err := tr.Retry(context.Background(), func(ctx context.Context) error {
+		n++
+		return e
+	})
+	require.Equal(t, ErrBreakerOpen, err)
+	require.Equal(t, 2, n)
+	require.Equal(t, BreakerOpen, b.State())
+}
+
+func TestTrierWithBreakerNotOK(t *testing.T) {
+	c := triertest.NewClock(time.Unix(0, 0))
+	b := NewBreaker(BreakerConsecutiveFailures(2), BreakerCooldown(time.Second), BreakerClock(c))
+	tr := NewTrierWithOptions(Constant(time.Millisecond), WithBreaker(b))
+	n := 0
+	fn := func(ctx context.Context) (bool, error) {
+		n++
+		return false, nil
+	}
+	ok, err := tr.Try(context.Background(), fn)
+	require.False(t, ok)
+	require.Equal(t, ErrBreakerOpen, err)
+	require.Equal(t, 2, n)
+	require.Equal(t, BreakerOpen, b.State())
+
+	c.Advance(time.Second)
+	ok, err = NewTrierWithOptions(Constant(time.Millisecond), WithMaxRetries(0), WithBreaker(b)).Try(context.Background(), fn)
+	require.False(t, ok)
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+	require.Equal(t, BreakerOpen, b.State())
+}
+
+func TestTrierWithBreakerCancel(t *testing.T) {
+	b := NewBreaker(BreakerConsecutiveFailures(1))
+	tr := NewTrierWithOptions(Constant(time.Millisecond), WithBreaker(b))
+	ctx, cancel := context.WithCancel(context.Background())
+	err := tr.Retry(ctx, func(ctx context.Context) error {
+		cancel()
+		return ctx.Err()
+	})
+	require.Equal(t, context.Canceled, err)
+	require.Equal(t, BreakerClosed, b.State())
+}
@@ -0,0 +1,167 @@
+package trier
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrBudgetExhausted is the error returned by trier when retry budget does not allow retry.
+var ErrBudgetExhausted = errors.New("trier: retry budget exhausted")
+
+// Budget limits retries, could be shared by multiple triers.
+type Budget interface {
+	// Deposit records successful execution of retriable function.
+	Deposit()
+	// Withdraw reports whether retry is allowed, records the retry if it is.
+	Withdraw() bool
+}
+
+// WithBudget sets retry budget which is consulted before each delay between retries,
+// trier returns ErrBudgetExhausted if budget does not allow retry.
+func WithBudget(b Budget) Option {
+	return optionFunc(func(t *Trier) {
+		t.budget = b
+	})
+}
+
+type budgetOptions struct {
+	clock Clock
+}
+
+// BudgetOption configures retry budget.
+type BudgetOption func(*budgetOptions)
+
+// BudgetClock sets clock used by retry budget to measure time.
+func BudgetClock(c Clock) BudgetOption {
+	return func(o *budgetOptions) {
+		o.clock = c
+	}
+}
+
+func newBudgetOptions(options []BudgetOption) budgetOptions {
+	o := budgetOptions{clock: clock{}}
+	for _, fn := range options {
+		fn(&o)
+	}
+	return o
+}
+
+// TokenBucket is retry budget which allows retry if there is a token in the bucket,
+// tokens are added with constant rate up to the size of the bucket.
+type TokenBucket struct {
+	mu       sync.Mutex
+	clock    Clock
+	size     float64
+	interval time.Duration
+	tokens   float64
+	last     time.Time
+}
+
+// NewTokenBucket creates new full token bucket, one token is added per interval.
+func NewTokenBucket(size int, interval time.Duration, options ...BudgetOption) *TokenBucket {
+	c := newBudgetOptions(options).clock
+	return &TokenBucket{clock: c, size: float64(size), interval: interval, tokens: float64(size), last: c.Now()}
+}
+
+// Deposit does nothing, tokens are added with constant rate.
+func (b *TokenBucket) Deposit() {}
+
+// Withdraw takes token from the bucket.
+func (b *TokenBucket) Withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.clock.Now()
+	if b.interval > 0 && now.After(b.last) {
+		b.tokens += float64(now.Sub(b.last)) / float64(b.interval)
+		if b.tokens > b.size {
+			b.tokens = b.size
+		}
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// budgetSlots is the number of slots of sliding window of RatioBudget.
+const budgetSlots = 10
+
+type budgetSlot struct {
+	start                 time.Time
+	deposits, withdrawals int
+}
+
+// RatioBudget is retry budget which allows retries as ratio of successful executions
+// over sliding window, plus minimum number of retries per second.
+type RatioBudget struct {
+	mu     sync.Mutex
+	clock  Clock
+	ratio  float64
+	min    float64
+	window time.Duration
+	origin time.Time
+	slots  [budgetSlots]budgetSlot
+}
+
+// NewRatioBudget creates new ratio budget, e.g. ratio 0.1 allows 10% of successful executions to be retried.
+func NewRatioBudget(ratio float64, minPerSecond int, window time.Duration, options ...BudgetOption) *RatioBudget {
+	c := newBudgetOptions(options).clock
+	b := &RatioBudget{clock: c, ratio: ratio, min: float64(minPerSecond) * window.Seconds(), window: window}
+	b.origin = c.Now().Truncate(b.slotDuration())
+	return b
+}
+
+// Deposit records successful execution.
+func (b *RatioBudget) Deposit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.slot(b.clock.Now()).deposits++
+}
+
+// Withdraw reports whether number of retries over sliding window is less than allowed.
+func (b *RatioBudget) Withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.clock.Now()
+	var deposits, withdrawals int
+	for i := range b.slots {
+		s := &b.slots[i]
+		if now.Sub(s.start) < b.window {
+			deposits += s.deposits
+			withdrawals += s.withdrawals
+		}
+	}
+	if float64(withdrawals) >= b.ratio*float64(deposits)+b.min {
+		return false
+	}
+	b.slot(now).withdrawals++
+	return true
+}
+
+// slotDuration returns duration of slot of sliding window.
+func (b *RatioBudget) slotDuration() time.Duration {
+	d := b.window / budgetSlots
+	if d <= 0 {
+		d = 1
+	}
+	return d
+}
+
+// slot returns slot of sliding window for the time, resets the slot if it is expired.
+// Slots are numbered from the creation time of budget, the time could be before it.
+func (b *RatioBudget) slot(now time.Time) *budgetSlot {
+	d := b.slotDuration()
+	start := now.Truncate(d)
+	i := (start.Sub(b.origin) / d) % budgetSlots
+	if i < 0 {
+		i += budgetSlots
+	}
+	s := &b.slots[i]
+	if !s.start.Equal(start) {
+		*s = budgetSlot{start: start}
+	}
+	return s
+}
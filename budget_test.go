@@ -0,0 +1,101 @@
+package trier_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/da440dil/go-trier"
+	"github.com/da440dil/go-trier/triertest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenBucket(t *testing.T) {
+	c := triertest.NewClock(time.Unix(0, 0))
+	b := NewTokenBucket(2, time.Second, BudgetClock(c))
+
+	b.Deposit()
+	require.True(t, b.Withdraw())
+	require.True(t, b.Withdraw())
+	require.False(t, b.Withdraw())
+
+	c.Advance(time.Millisecond * 500)
+	require.False(t, b.Withdraw())
+	c.Advance(time.Millisecond * 500)
+	require.True(t, b.Withdraw())
+	require.False(t, b.Withdraw())
+
+	c.Advance(time.Hour)
+	require.True(t, b.Withdraw())
+	require.True(t, b.Withdraw())
+	require.False(t, b.Withdraw())
+}
+
+func TestRatioBudget(t *testing.T) {
+	c := triertest.NewClock(time.Unix(0, 0))
+	b := NewRatioBudget(0.5, 1, time.Second*10, BudgetClock(c))
+
+	for i := 0; i < 10; i++ {
+		require.True(t, b.Withdraw())
+	}
+	require.False(t, b.Withdraw())
+
+	for i := 0; i < 4; i++ {
+		b.Deposit()
+	}
+	require.True(t, b.Withdraw())
+	require.True(t, b.Withdraw())
+	require.False(t, b.Withdraw())
+
+	c.Advance(time.Second * 5)
+	require.False(t, b.Withdraw())
+	c.Advance(time.Second * 5)
+	for i := 0; i < 10; i++ {
+		require.True(t, b.Withdraw())
+	}
+	require.False(t, b.Withdraw())
+}
+
+func TestRatioBudgetZeroTime(t *testing.T) {
+	c := triertest.NewClock(time.Time{})
+	b := NewRatioBudget(0, 1, time.Second*10, BudgetClock(c))
+	for i := 0; i < 10; i++ {
+		require.True(t, b.Withdraw())
+	}
+	require.False(t, b.Withdraw())
+
+	c.Advance(time.Second * 10)
+	for i := 0; i < 10; i++ {
+		require.True(t, b.Withdraw())
+	}
+	require.False(t, b.Withdraw())
+}
+
+func TestTrierWithBudget(t *testing.T) {
+	b := NewTokenBucket(2, time.Hour)
+	tr := NewTrierWithOptions(Constant(time.Millisecond), WithBudget(b))
+	e := errors.New("some error")
+	n := 0
+	err := tr.Retry(context.Background(), func(ctx context.Context) error {
+		n++
+		return e
+	})
+	require.Equal(t, ErrBudgetExhausted, err)
+	require.Equal(t, 3, n)
+
+	r := NewRatioBudget(1, 0, time.Minute)
+	tr = NewTrierWithOptions(Constant(time.Millisecond), WithBudget(r))
+	ok, err := tr.Try(context.Background(), func(ctx context.Context) (bool, error) {
+		return true, nil
+	})
+	require.NoError(t, err)
+	require.True(t, ok)
+	n = 0
+	err = tr.Retry(context.Background(), func(ctx context.Context) error {
+		n++
+		return e
+	})
+	require.Equal(t, ErrBudgetExhausted, err)
+	require.Equal(t, 2, n)
+}
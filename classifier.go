@@ -0,0 +1,60 @@
+package trier
+
+import (
+	"context"
+	"errors"
+	"net"
+)
+
+// Action defines behavior of trier when retriable function returns error.
+type Action int
+
+const (
+	// ActionDefault leaves decision to the next classifier, if there is none
+	// Try and Run return the error, Retry retries execution.
+	ActionDefault Action = iota
+	// ActionRetry retries execution.
+	ActionRetry
+	// ActionStop stops retries as if iterator is exhausted.
+	ActionStop
+	// ActionAbort returns the error without retries.
+	ActionAbort
+)
+
+// Classifier maps error returned by retriable function to action of trier.
+type Classifier func(err error) Action
+
+// WithClassifier adds classifier of errors returned by retriable function,
+// classifiers are applied in order until one of them returns action other than ActionDefault.
+// Errors wrapped using Permanent are never classified.
+func WithClassifier(c Classifier) Option {
+	return optionFunc(func(t *Trier) {
+		t.classifiers = append(t.classifiers, c)
+	})
+}
+
+func (t Trier) classify(err error) Action {
+	for _, c := range t.classifiers {
+		if a := c(err); a != ActionDefault {
+			return a
+		}
+	}
+	return ActionDefault
+}
+
+// ClassifyContext aborts on context.Canceled and context.DeadlineExceeded errors.
+func ClassifyContext(err error) Action {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return ActionAbort
+	}
+	return ActionDefault
+}
+
+// ClassifyNet retries on net.Error errors which are timeouts.
+func ClassifyNet(err error) Action {
+	var e net.Error
+	if errors.As(err, &e) && e.Timeout() {
+		return ActionRetry
+	}
+	return ActionDefault
+}
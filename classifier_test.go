@@ -0,0 +1,93 @@
+package trier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type timeoutError bool
+
+func (e timeoutError) Error() string   { return "timeout error" }
+func (e timeoutError) Timeout() bool   { return bool(e) }
+func (e timeoutError) Temporary() bool { return false }
+
+var _ net.Error = timeoutError(false)
+
+func TestClassifyContext(t *testing.T) {
+	require.Equal(t, ActionAbort, ClassifyContext(context.Canceled))
+	require.Equal(t, ActionAbort, ClassifyContext(fmt.Errorf("wrapped: %w", context.DeadlineExceeded)))
+	require.Equal(t, ActionDefault, ClassifyContext(errors.New("some error")))
+}
+
+func TestClassifyNet(t *testing.T) {
+	require.Equal(t, ActionRetry, ClassifyNet(timeoutError(true)))
+	require.Equal(t, ActionRetry, ClassifyNet(fmt.Errorf("wrapped: %w", timeoutError(true))))
+	require.Equal(t, ActionDefault, ClassifyNet(timeoutError(false)))
+	require.Equal(t, ActionDefault, ClassifyNet(errors.New("some error")))
+}
+
+func TestWithClassifier(t *testing.T) {
+	b := WithMaxRetries(2)(Constant(time.Millisecond))
+	e := errors.New("some error")
+	s := errors.New("stop error")
+	tr := NewTrierWithOptions(b, WithClassifier(ClassifyNet), WithClassifier(func(err error) Action {
+		if err == s {
+			return ActionStop
+		}
+		return ActionDefault
+	}))
+	ctx := context.Background()
+
+	n := 0
+	ok, err := tr.Try(ctx, func(ctx context.Context) (bool, error) {
+		n++
+		return false, timeoutError(true)
+	})
+	require.False(t, ok)
+	require.Equal(t, timeoutError(true), err)
+	require.Equal(t, 3, n)
+
+	n = 0
+	ok, err = tr.Try(ctx, func(ctx context.Context) (bool, error) {
+		n++
+		return false, e
+	})
+	require.False(t, ok)
+	require.Equal(t, e, err)
+	require.Equal(t, 1, n)
+
+	n = 0
+	err = tr.Run(ctx, func(ctx context.Context) (bool, error) {
+		n++
+		return false, s
+	})
+	require.True(t, errors.Is(err, ErrExhausted))
+	require.True(t, errors.Is(err, s))
+	require.Equal(t, 1, n)
+
+	tr = NewTrierWithOptions(b, WithClassifier(ClassifyContext))
+	n = 0
+	err = tr.Retry(ctx, func(ctx context.Context) error {
+		n++
+		return context.DeadlineExceeded
+	})
+	require.Equal(t, context.DeadlineExceeded, err)
+	require.Equal(t, 1, n)
+
+	tr = NewTrierWithOptions(b, WithClassifier(func(err error) Action {
+		return ActionRetry
+	}))
+	n = 0
+	err = tr.Retry(ctx, func(ctx context.Context) error {
+		n++
+		return Permanent(e)
+	})
+	require.True(t, IsPermanent(err))
+	require.Equal(t, 1, n)
+}
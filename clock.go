@@ -0,0 +1,50 @@
+package trier
+
+import "time"
+
+// Clock defines time functions used by trier.
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer defines timer created by clock.
+type Timer interface {
+	C() <-chan time.Time
+	Reset(d time.Duration) bool
+	Stop() bool
+}
+
+type clock struct{}
+
+func (clock) Now() time.Time {
+	return time.Now()
+}
+
+func (clock) NewTimer(d time.Duration) Timer {
+	return timer{time.NewTimer(d)}
+}
+
+type timer struct {
+	t *time.Timer
+}
+
+func (t timer) C() <-chan time.Time {
+	return t.t.C
+}
+
+func (t timer) Reset(d time.Duration) bool {
+	return t.t.Reset(d)
+}
+
+func (t timer) Stop() bool {
+	return t.t.Stop()
+}
+
+// WithClock sets clock used by trier to measure time and to wait between retries,
+// context deadlines and attempt timeouts are still measured using real time.
+func WithClock(c Clock) Option {
+	return optionFunc(func(t *Trier) {
+		t.clock = c
+	})
+}
@@ -0,0 +1,100 @@
+package trier
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrDeadline is the error returned by Try when delay between retries exceeds context deadline.
+var ErrDeadline = errors.New("trier: delay exceeds context deadline")
+
+// ErrExhausted is the error matched by ExhaustedError using errors.Is.
+var ErrExhausted = errors.New("trier: retries exhausted")
+
+// ExhaustedError is the error returned by Run when iterator is exhausted.
+type ExhaustedError struct {
+	// Attempts is the number of executions of retriable function.
+	Attempts int
+	// Elapsed is the time elapsed since the first execution of retriable function.
+	Elapsed time.Duration
+	// Err is the error returned by the last execution of retriable function,
+	// nil if the execution success flag equals false.
+	Err error
+}
+
+func (e *ExhaustedError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("%v after %v attempts in %v", ErrExhausted, e.Attempts, e.Elapsed)
+	}
+	return fmt.Sprintf("%v after %v attempts in %v: %v", ErrExhausted, e.Attempts, e.Elapsed, e.Err)
+}
+
+// Unwrap returns the error returned by the last execution of retriable function.
+func (e *ExhaustedError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrExhausted.
+func (e *ExhaustedError) Is(target error) bool {
+	return target == ErrExhausted
+}
+
+// ErrPermanent is the error matched by PermanentError using errors.Is.
+var ErrPermanent = errors.New("trier: permanent error")
+
+// PermanentError is the error which stops retries.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrPermanent.
+func (e *PermanentError) Is(target error) bool {
+	return target == ErrPermanent
+}
+
+// Permanent wraps error to stop retries, returns nil if error is nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{err}
+}
+
+// IsPermanent reports whether any error in error chain is PermanentError.
+func IsPermanent(err error) bool {
+	return errors.Is(err, ErrPermanent)
+}
+
+// RetryAfterError is the error which suggests delay before the next execution of retriable function,
+// trier retries execution unless a classifier decides otherwise.
+type RetryAfterError struct {
+	Err   error
+	Delay time.Duration
+}
+
+func (e *RetryAfterError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("trier: retry after %v", e.Delay)
+	}
+	return fmt.Sprintf("%v: retry after %v", e.Err, e.Delay)
+}
+
+// Unwrap returns the wrapped error.
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
+
+// RetryAfter wraps error to suggest delay before the next execution of retriable function.
+func RetryAfter(err error, d time.Duration) error {
+	return &RetryAfterError{err, d}
+}
@@ -0,0 +1,39 @@
+package trier
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPermanent(t *testing.T) {
+	require.NoError(t, Permanent(nil))
+
+	e := errors.New("some error")
+	err := Permanent(e)
+	require.Equal(t, "some error", err.Error())
+	require.True(t, errors.Is(err, e))
+	var p *PermanentError
+	require.True(t, errors.As(err, &p))
+	require.Equal(t, e, p.Err)
+	require.True(t, errors.Is(err, ErrPermanent))
+	require.True(t, IsPermanent(err))
+	require.True(t, IsPermanent(fmt.Errorf("wrapped: %w", err)))
+	require.False(t, IsPermanent(e))
+	require.False(t, IsPermanent(nil))
+}
+
+func TestRetryAfter(t *testing.T) {
+	e := errors.New("some error")
+	err := RetryAfter(e, time.Second)
+	require.Equal(t, "some error: retry after 1s", err.Error())
+	require.True(t, errors.Is(err, e))
+	var r *RetryAfterError
+	require.True(t, errors.As(err, &r))
+	require.Equal(t, time.Second, r.Delay)
+
+	require.Equal(t, "trier: retry after 1s", RetryAfter(nil, time.Second).Error())
+}
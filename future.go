@@ -0,0 +1,54 @@
+package trier
+
+import "context"
+
+// TryResult is the result of asynchronous execution of retriable function.
+type TryResult struct {
+	OK  bool
+	Err error
+}
+
+// Future is the handle of asynchronous execution of retriable function.
+type Future struct {
+	done   chan struct{}
+	result chan TryResult
+	cancel context.CancelFunc
+	ok     bool
+	err    error
+}
+
+// TryAsync executes retriable function the same way as Try in a new goroutine.
+func (t Trier) TryAsync(ctx context.Context, fn Retriable) *Future {
+	ctx, cancel := context.WithCancel(ctx)
+	f := &Future{done: make(chan struct{}), result: make(chan TryResult, 1), cancel: cancel}
+	go func() {
+		defer close(f.done)
+		defer cancel()
+		f.ok, f.err = t.Try(ctx, fn)
+		f.result <- TryResult{f.ok, f.err}
+	}()
+	return f
+}
+
+// Done returns channel which is closed when execution finishes, the result is returned by Wait.
+func (f *Future) Done() <-chan struct{} {
+	return f.done
+}
+
+// Result returns channel which receives the result when execution finishes,
+// the result is sent once, so the channel should have a single receiver.
+func (f *Future) Result() <-chan TryResult {
+	return f.result
+}
+
+// Wait waits for execution to finish, returns the result of Try.
+func (f *Future) Wait() (bool, error) {
+	<-f.done
+	return f.ok, f.err
+}
+
+// Cancel cancels context of execution, the goroutine exits as soon as retriable function returns,
+// the timer of delay between retries is stopped immediately.
+func (f *Future) Cancel() {
+	f.cancel()
+}
@@ -0,0 +1,71 @@
+package trier
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTryAsync(t *testing.T) {
+	tr := NewTrier(Constant(time.Millisecond), WithMaxRetries(2))
+	n := 0
+	f := tr.TryAsync(context.Background(), func(ctx context.Context) (bool, error) {
+		n++
+		return n == 2, nil
+	})
+	<-f.Done()
+	ok, err := f.Wait()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, 2, n)
+
+	e := errors.New("some error")
+	f = tr.TryAsync(context.Background(), func(ctx context.Context) (bool, error) {
+		return false, e
+	})
+	ok, err = f.Wait()
+	require.Equal(t, e, err)
+	require.False(t, ok)
+	require.Equal(t, TryResult{false, e}, <-f.Result())
+}
+
+func TestTryAsyncResult(t *testing.T) {
+	tr := NewTrier(Constant(time.Millisecond), WithMaxRetries(2))
+	f := tr.TryAsync(context.Background(), func(ctx context.Context) (bool, error) {
+		return true, nil
+	})
+	select {
+	case r := <-f.Result():
+		require.Equal(t, TryResult{OK: true}, r)
+	case <-time.After(time.Second):
+		t.Fatal("execution did not finish")
+	}
+	ok, err := f.Wait()
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func TestTryAsyncCancel(t *testing.T) {
+	n := runtime.NumGoroutine()
+	tr := NewTrier(Constant(time.Hour))
+	f := tr.TryAsync(context.Background(), func(ctx context.Context) (bool, error) {
+		return false, nil
+	})
+	select {
+	case <-f.Done():
+		t.Fatal("execution finished too early")
+	case <-time.After(time.Millisecond * 10):
+	}
+	f.Cancel()
+	ok, err := f.Wait()
+	require.Equal(t, context.Canceled, err)
+	require.False(t, ok)
+	for i := 0; i < 100 && runtime.NumGoroutine() > n; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	require.True(t, runtime.NumGoroutine() <= n)
+}
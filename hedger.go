@@ -0,0 +1,121 @@
+package trier
+
+import (
+	"context"
+	"time"
+)
+
+// Hedger defines parameters for executing hedged functions: if execution does not finish within delay
+// created by iterator, another execution starts concurrently, the first successful result wins.
+type Hedger struct {
+	b     Iterable
+	max   int
+	clock Clock
+}
+
+// HedgerOption configures hedger.
+type HedgerOption interface {
+	applyHedger(*Hedger)
+}
+
+type hedgerOptionFunc func(*Hedger)
+
+func (fn hedgerOptionFunc) applyHedger(h *Hedger) {
+	fn(h)
+}
+
+func (fn Decorator) applyHedger(h *Hedger) {
+	h.b = fn(h.b)
+}
+
+// HedgerClock sets clock used by hedger to measure time and wait for delays.
+func HedgerClock(c Clock) HedgerOption {
+	return hedgerOptionFunc(func(h *Hedger) {
+		h.clock = c
+	})
+}
+
+// NewHedger creates new hedger which runs at most max executions concurrently, decorators are options too.
+func NewHedger(b Iterable, max int, options ...HedgerOption) Hedger {
+	if max < 1 {
+		max = 1
+	}
+	h := Hedger{b: b, max: max, clock: clock{}}
+	for _, o := range options {
+		o.applyHedger(&h)
+	}
+	return h
+}
+
+// Do executes operation, starts another execution concurrently after each delay while previous ones are running
+// or after it if all of them failed. Returns nil after the first successful execution and cancels the others,
+// returns permanent error as is, returns ExhaustedError wrapping the last error if iterator is exhausted.
+func (h Hedger) Do(ctx context.Context, op Operation) error {
+	_, err := Hedge(ctx, h, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, op(ctx)
+	})
+	return err
+}
+
+type hedgeResult[T any] struct {
+	v   T
+	err error
+}
+
+// Hedge executes function using hedger, returns the value of the first successful execution.
+func Hedge[T any](ctx context.Context, h Hedger, fn func(ctx context.Context) (T, error)) (T, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// Each execution sends exactly one result, the buffer holds results of all running executions,
+	// so executions never block after hedge returns.
+	results := make(chan hedgeResult[T], h.max)
+	c := h.clock
+	if c == nil {
+		c = clock{}
+	}
+	begin := c.Now()
+	attempts, running := 0, 0
+	start := func(d time.Duration) {
+		attempts++
+		running++
+		actx := withAttempt(ctx, Attempt{attempts, c.Now().Sub(begin), d})
+		go func() {
+			v, err := fn(actx)
+			results <- hedgeResult[T]{v, err}
+		}()
+	}
+	start(0)
+	it := h.b.Iterator()
+	d, done := it.Next()
+	timer := c.NewTimer(d)
+	defer timer.Stop()
+	var zero T
+	var err error
+	for {
+		var tc <-chan time.Time
+		if !done && running < h.max {
+			tc = timer.C()
+		}
+		select {
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		case <-tc:
+			start(d)
+			d, done = it.Next()
+			timer.Reset(d)
+		case r := <-results:
+			running--
+			if r.err == nil {
+				return r.v, nil
+			}
+			err = r.err
+			if IsPermanent(err) {
+				return zero, err
+			}
+			// The next execution waits for the timer even if all executions failed, so failing fast never spins.
+			if running == 0 && done {
+				return zero, &ExhaustedError{Attempts: attempts, Elapsed: c.Now().Sub(begin), Err: err}
+			}
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package trier_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/da440dil/go-trier"
+	"github.com/da440dil/go-trier/triertest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHedge(t *testing.T) {
+	h := NewHedger(Constant(time.Millisecond*10), 3)
+	var n, cancelled int32
+	v, err := Hedge(context.Background(), h, func(ctx context.Context) (int, error) {
+		a, _ := AttemptFromContext(ctx)
+		atomic.AddInt32(&n, 1)
+		if a.Number == 2 {
+			return a.Number, nil
+		}
+		<-ctx.Done()
+		atomic.AddInt32(&cancelled, 1)
+		return 0, ctx.Err()
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, v)
+	require.Equal(t, int32(2), atomic.LoadInt32(&n))
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&cancelled) == 1
+	}, time.Second, time.Millisecond)
+}
+
+func TestHedgeMax(t *testing.T) {
+	h := NewHedger(Constant(time.Millisecond), 2, WithMaxRetries(3))
+	var running, max int32
+	e := errors.New("some error")
+	err := h.Do(context.Background(), func(ctx context.Context) error {
+		r := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&max)
+			if r <= m || atomic.CompareAndSwapInt32(&max, m, r) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond * 20)
+		return e
+	})
+	require.True(t, errors.Is(err, ErrExhausted))
+	require.True(t, errors.Is(err, e))
+	var x *ExhaustedError
+	require.True(t, errors.As(err, &x))
+	require.Equal(t, 4, x.Attempts)
+	require.Equal(t, int32(2), atomic.LoadInt32(&max))
+}
+
+func TestHedgeFailures(t *testing.T) {
+	h := NewHedger(Constant(time.Hour), 0, WithMaxRetries(2), HedgerClock(triertest.NewAutoClock(time.Unix(0, 0))))
+	e := errors.New("some error")
+	var n int32
+	err := h.Do(context.Background(), func(ctx context.Context) error {
+		atomic.AddInt32(&n, 1)
+		return e
+	})
+	require.True(t, errors.Is(err, ErrExhausted))
+	require.Equal(t, int32(3), atomic.LoadInt32(&n))
+
+	atomic.StoreInt32(&n, 0)
+	err = h.Do(context.Background(), func(ctx context.Context) error {
+		atomic.AddInt32(&n, 1)
+		return Permanent(e)
+	})
+	require.True(t, IsPermanent(err))
+	require.Equal(t, int32(1), atomic.LoadInt32(&n))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+	err = h.Do(ctx, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestHedgeWaitsAfterFailures(t *testing.T) {
+	c := triertest.NewClock(time.Unix(0, 0))
+	h := NewHedger(Constant(time.Second), 1, WithMaxRetries(2), HedgerClock(c))
+	e := errors.New("some error")
+	attempts := make(chan Attempt, 3)
+	result := make(chan error, 1)
+	go func() {
+		result <- h.Do(context.Background(), func(ctx context.Context) error {
+			a, _ := AttemptFromContext(ctx)
+			attempts <- a
+			return e
+		})
+	}()
+
+	require.Equal(t, Attempt{Number: 1}, <-attempts)
+	for i := 2; i <= 3; i++ {
+		c.BlockUntil(1)
+		select {
+		case a := <-attempts:
+			t.Fatalf("attempt %v started without waiting", a.Number)
+		case <-time.After(time.Millisecond * 10):
+		}
+		c.Advance(time.Second)
+		require.Equal(t, Attempt{Number: i, Elapsed: time.Second * time.Duration(i-1), Delay: time.Second}, <-attempts)
+	}
+	err := <-result
+	require.True(t, errors.Is(err, ErrExhausted))
+	var x *ExhaustedError
+	require.True(t, errors.As(err, &x))
+	require.Equal(t, 3, x.Attempts)
+	require.Equal(t, time.Second*2, x.Elapsed)
+}
@@ -0,0 +1,30 @@
+package trier
+
+import (
+	"context"
+	"time"
+)
+
+// WithBeforeAttempt sets function which is called before each execution of retriable function,
+// attempt numbers start from 1.
+func WithBeforeAttempt(fn func(ctx context.Context, attempt int)) Option {
+	return optionFunc(func(t *Trier) {
+		t.before = fn
+	})
+}
+
+// WithOnRetry sets function which is called after each failed execution of retriable function
+// which is going to be retried, with the error of the execution and the delay before the next one.
+func WithOnRetry(fn func(ctx context.Context, attempt int, err error, d time.Duration)) Option {
+	return optionFunc(func(t *Trier) {
+		t.retry = fn
+	})
+}
+
+// WithOnGiveUp sets function which is called when trier stops retries without success,
+// with the number of executions of retriable function and the error returned by trier.
+func WithOnGiveUp(fn func(ctx context.Context, attempts int, err error)) Option {
+	return optionFunc(func(t *Trier) {
+		t.giveUp = fn
+	})
+}
@@ -0,0 +1,54 @@
+package trier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHooks(t *testing.T) {
+	var events []string
+	tr := NewTrierWithOptions(
+		Linear(time.Millisecond),
+		WithMaxRetries(2),
+		WithBeforeAttempt(func(ctx context.Context, attempt int) {
+			events = append(events, fmt.Sprintf("before #%v", attempt))
+		}),
+		WithOnRetry(func(ctx context.Context, attempt int, err error, d time.Duration) {
+			events = append(events, fmt.Sprintf("retry #%v: %v, %v", attempt, err, d))
+		}),
+		WithOnGiveUp(func(ctx context.Context, attempts int, err error) {
+			events = append(events, fmt.Sprintf("give up after %v: %v", attempts, errors.Is(err, ErrExhausted)))
+		}),
+	)
+	e := errors.New("some error")
+
+	err := tr.Retry(context.Background(), func(ctx context.Context) error {
+		return e
+	})
+	require.True(t, errors.Is(err, ErrExhausted))
+	require.Equal(t, []string{
+		"before #1",
+		"retry #1: some error, 1ms",
+		"before #2",
+		"retry #2: some error, 2ms",
+		"before #3",
+		"give up after 3: true",
+	}, events)
+
+	events = nil
+	ok, err := tr.Try(context.Background(), func(ctx context.Context) (bool, error) {
+		return len(events) > 2, nil
+	})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []string{
+		"before #1",
+		"retry #1: <nil>, 1ms",
+		"before #2",
+	}, events)
+}
@@ -1,7 +1,9 @@
 package trier
 
 import (
+	"math"
 	"math/rand"
+	"sync"
 	"time"
 )
 
@@ -15,6 +17,21 @@ type Iterable interface {
 	Iterator() Iterator
 }
 
+// maxDuration is the maximum duration as float64.
+const maxDuration = float64(math.MaxInt64)
+
+// add returns sum of durations, saturates at the maximum or minimum duration instead of overflowing.
+func add(a, b time.Duration) time.Duration {
+	c := a + b
+	if a > 0 && b > 0 && c < 0 {
+		return math.MaxInt64
+	}
+	if a < 0 && b < 0 && c >= 0 {
+		return math.MinInt64
+	}
+	return c
+}
+
 type constant time.Duration
 
 func (i constant) Next() (time.Duration, bool) {
@@ -34,7 +51,7 @@ type linear struct {
 }
 
 func (i *linear) Next() (time.Duration, bool) {
-	i.d += i.rate
+	i.d = add(i.d, i.rate)
 	return i.d, false
 }
 
@@ -53,7 +70,7 @@ type linearRate struct {
 
 func (i *linearRate) Next() (time.Duration, bool) {
 	v := i.d
-	i.d += i.rate
+	i.d = add(i.d, i.rate)
 	return v, false
 }
 
@@ -69,9 +86,9 @@ func LinearRate(d, rate time.Duration) Iterable {
 type exponential time.Duration
 
 func (i *exponential) Next() (time.Duration, bool) {
-	v := *i
-	*i = v + v
-	return time.Duration(v), false
+	v := time.Duration(*i)
+	*i = exponential(add(v, v))
+	return v, false
 }
 
 func (i exponential) Iterator() Iterator {
@@ -89,6 +106,12 @@ type exponentialRate struct {
 
 func (i *exponentialRate) Next() (time.Duration, bool) {
 	v := i.d
+	if v >= maxDuration {
+		return math.MaxInt64, false
+	}
+	if v <= -maxDuration {
+		return math.MinInt64, false
+	}
 	i.d += i.d * i.rate
 	return time.Duration(v), false
 }
@@ -107,7 +130,7 @@ type fibonacci struct {
 }
 
 func (i *fibonacci) Next() (time.Duration, bool) {
-	i.prev, i.curr = i.curr, i.prev+i.curr
+	i.prev, i.curr = i.curr, add(i.prev, i.curr)
 	return i.curr, false
 }
 
@@ -152,23 +175,185 @@ func WithMaxRetries(n int) Decorator {
 	}
 }
 
-var random *rand.Rand
+type maxTotalDelayB struct {
+	b Iterable
+	d time.Duration
+}
+
+func (b maxTotalDelayB) Iterator() Iterator {
+	return &maxTotalDelayI{i: b.b.Iterator(), d: b.d}
+}
+
+type maxTotalDelayI struct {
+	i      Iterator
+	d, sum time.Duration
+}
+
+func (i *maxTotalDelayI) Next() (time.Duration, bool) {
+	v, done := i.i.Next()
+	if done {
+		return 0, done
+	}
+	sum := add(i.sum, v)
+	if sum > i.d {
+		return 0, true
+	}
+	i.sum = sum
+	return v, done
+}
+
+// WithMaxTotalDelay sets maximum sum of delays between retries,
+// iteration stops if the next delay would exceed it.
+func WithMaxTotalDelay(d time.Duration) Decorator {
+	return func(b Iterable) Iterable {
+		return maxTotalDelayB{b, d}
+	}
+}
+
+type maxElapsedTimeB struct {
+	b Iterable
+	d time.Duration
+	c Clock
+}
+
+func (b maxElapsedTimeB) Iterator() Iterator {
+	return maxElapsedTimeI{b.b.Iterator(), b.d, b.c, b.c.Now()}
+}
+
+type maxElapsedTimeI struct {
+	i     Iterator
+	d     time.Duration
+	c     Clock
+	start time.Time
+}
+
+func (i maxElapsedTimeI) Next() (time.Duration, bool) {
+	v, done := i.i.Next()
+	if done {
+		return 0, done
+	}
+	if add(i.c.Now().Sub(i.start), v) > i.d {
+		return 0, true
+	}
+	return v, done
+}
+
+// WithMaxElapsedTime sets maximum time elapsed since iterator creation,
+// iteration stops if the next delay would exceed it.
+func WithMaxElapsedTime(d time.Duration) Decorator {
+	return WithMaxElapsedTimeClock(d, clock{})
+}
+
+// WithMaxElapsedTimeClock sets maximum time elapsed since iterator creation measured using the clock,
+// iteration stops if the next delay would exceed it.
+func WithMaxElapsedTimeClock(d time.Duration, c Clock) Decorator {
+	return func(b Iterable) Iterable {
+		return maxElapsedTimeB{b, d, c}
+	}
+}
+
+type maxDelayB struct {
+	b Iterable
+	d time.Duration
+}
+
+func (b maxDelayB) Iterator() Iterator {
+	return maxDelayI{b.b.Iterator(), b.d}
+}
+
+type maxDelayI struct {
+	i Iterator
+	d time.Duration
+}
+
+func (i maxDelayI) Next() (time.Duration, bool) {
+	v, done := i.i.Next()
+	if done {
+		return 0, done
+	}
+	if v > i.d {
+		v = i.d
+	}
+	return v, done
+}
+
+// WithMaxDelay sets maximum delay between retries.
+func WithMaxDelay(d time.Duration) Decorator {
+	return func(b Iterable) Iterable {
+		return maxDelayB{b, d}
+	}
+}
+
+type minDelayB struct {
+	b Iterable
+	d time.Duration
+}
 
-func init() {
-	random = rand.New(rand.NewSource(time.Now().UnixNano()))
+func (b minDelayB) Iterator() Iterator {
+	return minDelayI{b.b.Iterator(), b.d}
 }
 
+type minDelayI struct {
+	i Iterator
+	d time.Duration
+}
+
+func (i minDelayI) Next() (time.Duration, bool) {
+	v, done := i.i.Next()
+	if done {
+		return 0, done
+	}
+	if v < i.d {
+		v = i.d
+	}
+	return v, done
+}
+
+// WithMinDelay sets minimum delay between retries.
+func WithMinDelay(d time.Duration) Decorator {
+	return func(b Iterable) Iterable {
+		return minDelayB{b, d}
+	}
+}
+
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	v := s.src.Int63()
+	s.mu.Unlock()
+	return v
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	s.src.Seed(seed)
+	s.mu.Unlock()
+}
+
+// newRandom creates random numbers generator which is safe for concurrent use by multiple goroutines.
+func newRandom(src rand.Source) *rand.Rand {
+	return rand.New(&lockedSource{src: src})
+}
+
+var random = newRandom(rand.NewSource(time.Now().UnixNano()))
+
 type jitterB struct {
 	b    Iterable
+	r    *rand.Rand
 	n, j int64
 }
 
 func (b jitterB) Iterator() Iterator {
-	return jitterI{b.b.Iterator(), b.n, b.j}
+	return jitterI{b.b.Iterator(), b.r, b.n, b.j}
 }
 
 type jitterI struct {
 	i    Iterator
+	r    *rand.Rand
 	n, j int64
 }
 
@@ -177,7 +362,7 @@ func (i jitterI) Next() (time.Duration, bool) {
 	if done {
 		return 0, done
 	}
-	v = v + time.Duration(random.Int63n(i.n)-i.j)
+	v = add(v, time.Duration(i.r.Int63n(i.n)-i.j))
 	if v < 0 {
 		v = 0
 	}
@@ -186,8 +371,221 @@ func (i jitterI) Next() (time.Duration, bool) {
 
 // WithJitter sets maximum duration randomly added to or extracted from delay between retries to improve performance under high contention.
 func WithJitter(d time.Duration) Decorator {
+	return withJitter(d, random)
+}
+
+// WithJitterSource sets maximum duration randomly added to or extracted from delay between retries
+// using specified source of random numbers, the source is safe to share between iterators.
+func WithJitterSource(d time.Duration, src rand.Source) Decorator {
+	return withJitter(d, newRandom(src))
+}
+
+func withJitter(d time.Duration, r *rand.Rand) Decorator {
 	return func(b Iterable) Iterable {
 		j := int64(d)
-		return jitterB{b, j*2 + 1, j}
+		return jitterB{b, r, j*2 + 1, j}
+	}
+}
+
+// between returns random duration in the interval [min, max].
+func between(r *rand.Rand, min, max time.Duration) time.Duration {
+	if max <= min {
+		return min
+	}
+	n := int64(max - min)
+	if n == math.MaxInt64 {
+		return min + time.Duration(r.Int63())
+	}
+	return min + time.Duration(r.Int63n(n+1))
+}
+
+type fullJitterB struct {
+	b Iterable
+	r *rand.Rand
+}
+
+func (b fullJitterB) Iterator() Iterator {
+	return fullJitterI{b.b.Iterator(), b.r}
+}
+
+type fullJitterI struct {
+	i Iterator
+	r *rand.Rand
+}
+
+func (i fullJitterI) Next() (time.Duration, bool) {
+	v, done := i.i.Next()
+	if done {
+		return 0, done
+	}
+	return between(i.r, 0, v), done
+}
+
+// WithFullJitter sets delay between retries to random duration between zero and delay.
+func WithFullJitter() Decorator {
+	return withFullJitter(random)
+}
+
+// WithFullJitterSource sets delay between retries to random duration between zero and delay
+// using specified source of random numbers, the source is safe to share between iterators.
+func WithFullJitterSource(src rand.Source) Decorator {
+	return withFullJitter(newRandom(src))
+}
+
+func withFullJitter(r *rand.Rand) Decorator {
+	return func(b Iterable) Iterable {
+		return fullJitterB{b, r}
+	}
+}
+
+type equalJitterB struct {
+	b Iterable
+	r *rand.Rand
+}
+
+func (b equalJitterB) Iterator() Iterator {
+	return equalJitterI{b.b.Iterator(), b.r}
+}
+
+type equalJitterI struct {
+	i Iterator
+	r *rand.Rand
+}
+
+func (i equalJitterI) Next() (time.Duration, bool) {
+	v, done := i.i.Next()
+	if done {
+		return 0, done
+	}
+	if v < 0 {
+		return 0, done
+	}
+	h := v / 2
+	return h + between(i.r, 0, v-h), done
+}
+
+// WithEqualJitter sets delay between retries to half of delay plus random duration between zero and half of delay.
+func WithEqualJitter() Decorator {
+	return withEqualJitter(random)
+}
+
+// WithEqualJitterSource sets delay between retries to half of delay plus random duration between zero and half of delay
+// using specified source of random numbers, the source is safe to share between iterators.
+func WithEqualJitterSource(src rand.Source) Decorator {
+	return withEqualJitter(newRandom(src))
+}
+
+func withEqualJitter(r *rand.Rand) Decorator {
+	return func(b Iterable) Iterable {
+		return equalJitterB{b, r}
+	}
+}
+
+type decorrelatedJitterB struct {
+	b   Iterable
+	r   *rand.Rand
+	max time.Duration
+}
+
+func (b decorrelatedJitterB) Iterator() Iterator {
+	return &decorrelatedJitterI{i: b.b.Iterator(), r: b.r, max: b.max}
+}
+
+type decorrelatedJitterI struct {
+	i         Iterator
+	r         *rand.Rand
+	max, prev time.Duration
+}
+
+func (i *decorrelatedJitterI) Next() (time.Duration, bool) {
+	v, done := i.i.Next()
+	if done {
+		return 0, done
+	}
+	if v < 0 {
+		v = 0
+	}
+	if i.prev < v {
+		i.prev = v
+	}
+	u := i.prev * 3
+	if u/3 != i.prev {
+		u = math.MaxInt64
+	}
+	v = between(i.r, v, u)
+	if i.max > 0 && v > i.max {
+		v = i.max
+	}
+	i.prev = v
+	return v, done
+}
+
+// WithDecorrelatedJitter sets delay between retries to random duration between delay
+// and three times previous delay, the result is capped by maximum duration if it is greater than zero.
+func WithDecorrelatedJitter(max time.Duration) Decorator {
+	return withDecorrelatedJitter(max, random)
+}
+
+// WithDecorrelatedJitterSource sets delay between retries to random duration between delay and three times previous delay
+// using specified source of random numbers, the source is safe to share between iterators.
+func WithDecorrelatedJitterSource(max time.Duration, src rand.Source) Decorator {
+	return withDecorrelatedJitter(max, newRandom(src))
+}
+
+func withDecorrelatedJitter(max time.Duration, r *rand.Rand) Decorator {
+	return func(b Iterable) Iterable {
+		return decorrelatedJitterB{b, r, max}
+	}
+}
+
+type jitterFactorB struct {
+	b Iterable
+	r *rand.Rand
+	f float64
+}
+
+func (b jitterFactorB) Iterator() Iterator {
+	return jitterFactorI{b.b.Iterator(), b.r, b.f}
+}
+
+type jitterFactorI struct {
+	i Iterator
+	r *rand.Rand
+	f float64
+}
+
+func (i jitterFactorI) Next() (time.Duration, bool) {
+	v, done := i.i.Next()
+	if done {
+		return 0, done
+	}
+	if v <= 0 {
+		return 0, done
+	}
+	x := float64(v) * (1 + i.f*(2*i.r.Float64()-1))
+	if x >= math.MaxInt64 {
+		return math.MaxInt64, done
+	}
+	if x < 0 {
+		return 0, done
+	}
+	return time.Duration(x), done
+}
+
+// WithJitterFactor sets fraction of delay randomly added to or extracted from delay between retries,
+// e.g. 0.2 randomizes each delay by ±20%.
+func WithJitterFactor(f float64) Decorator {
+	return withJitterFactor(f, random)
+}
+
+// WithJitterFactorSource sets fraction of delay randomly added to or extracted from delay between retries
+// using specified source of random numbers, the source is safe to share between iterators.
+func WithJitterFactorSource(f float64, src rand.Source) Decorator {
+	return withJitterFactor(f, newRandom(src))
+}
+
+func withJitterFactor(f float64, r *rand.Rand) Decorator {
+	return func(b Iterable) Iterable {
+		return jitterFactorB{b, r, f}
 	}
 }
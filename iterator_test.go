@@ -1,10 +1,15 @@
-package trier
+package trier_test
 
 import (
 	"fmt"
+	"math"
+	"math/rand"
+	"sync"
 	"testing"
 	"time"
 
+	. "github.com/da440dil/go-trier"
+	"github.com/da440dil/go-trier/triertest"
 	"github.com/stretchr/testify/require"
 )
 
@@ -204,6 +209,48 @@ func ExampleFibonacci() {
 	// #4: { 80ms, false }
 }
 
+func TestSaturation(t *testing.T) {
+	max := time.Duration(math.MaxInt64)
+	min := time.Duration(math.MinInt64)
+	tests := map[string]struct {
+		b Iterable
+		d time.Duration
+	}{
+		"Linear":                  {Linear(max / 2), max},
+		"LinearRate":              {LinearRate(max/2, max/2), max},
+		"LinearRateNegative":      {LinearRate(min/2, min/2), min},
+		"Exponential":             {Exponential(time.Second), max},
+		"ExponentialRate":         {ExponentialRate(time.Second, 1), max},
+		"ExponentialRateNegative": {ExponentialRate(-time.Second, 1), min},
+		"Fibonacci":               {Fibonacci(time.Second), max},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			it := tc.b.Iterator()
+			for i := 0; i < 100; i++ {
+				it.Next()
+			}
+			for i := 0; i < 3; i++ {
+				d, done := it.Next()
+				require.Equal(t, tc.d, d)
+				require.False(t, done)
+			}
+		})
+	}
+
+	t.Run("WithJitter", func(t *testing.T) {
+		it := WithJitter(time.Second)(Exponential(time.Second)).Iterator()
+		for i := 0; i < 100; i++ {
+			it.Next()
+		}
+		for i := 0; i < 1000; i++ {
+			d, done := it.Next()
+			require.True(t, max-time.Second <= d, d)
+			require.False(t, done)
+		}
+	})
+}
+
 func TestWithMaxRetries(t *testing.T) {
 	b := Constant(time.Second)
 	b = WithMaxRetries(3)(b)
@@ -263,6 +310,114 @@ func ExampleWithMaxRetries_exponential() {
 	// #3: { 0s, true }
 }
 
+func TestWithMaxTotalDelay(t *testing.T) {
+	b := Linear(time.Second)
+	b = WithMaxTotalDelay(time.Second * 7)(b)
+	for i := 0; i < 3; i++ {
+		it := b.Iterator()
+		d, done := it.Next()
+		require.Equal(t, time.Second, d)
+		require.False(t, done)
+		d, done = it.Next()
+		require.Equal(t, time.Second*2, d)
+		require.False(t, done)
+		d, done = it.Next()
+		require.Equal(t, time.Second*3, d)
+		require.False(t, done)
+		d, done = it.Next()
+		require.Equal(t, time.Duration(0), d)
+		require.True(t, done)
+	}
+
+	// for test coverage
+	it := WithMaxTotalDelay(time.Second)(WithMaxRetries(0)(Constant(time.Second))).Iterator()
+	d, done := it.Next()
+	require.Equal(t, time.Duration(0), d)
+	require.True(t, done)
+}
+
+func TestWithMaxElapsedTime(t *testing.T) {
+	c := triertest.NewClock(time.Unix(0, 0))
+	b := Constant(time.Millisecond * 40)
+	b = WithMaxElapsedTimeClock(time.Millisecond*100, c)(b)
+	it := b.Iterator()
+	d, done := it.Next()
+	require.Equal(t, time.Millisecond*40, d)
+	require.False(t, done)
+	c.Advance(d)
+	d, done = it.Next()
+	require.Equal(t, time.Millisecond*40, d)
+	require.False(t, done)
+	c.Advance(d)
+	d, done = it.Next()
+	require.Equal(t, time.Duration(0), d)
+	require.True(t, done)
+
+	// for test coverage
+	it = WithMaxElapsedTime(time.Second)(WithMaxRetries(0)(Constant(time.Second))).Iterator()
+	d, done = it.Next()
+	require.Equal(t, time.Duration(0), d)
+	require.True(t, done)
+}
+
+func TestWithMaxDelay(t *testing.T) {
+	b := Exponential(time.Second)
+	b = WithMaxRetries(4)(b)
+	b = WithMaxDelay(time.Second * 3)(b)
+	for i := 0; i < 3; i++ {
+		it := b.Iterator()
+		d, done := it.Next()
+		require.Equal(t, time.Second, d)
+		require.False(t, done)
+		d, done = it.Next()
+		require.Equal(t, time.Second*2, d)
+		require.False(t, done)
+		d, done = it.Next()
+		require.Equal(t, time.Second*3, d)
+		require.False(t, done)
+		d, done = it.Next()
+		require.Equal(t, time.Second*3, d)
+		require.False(t, done)
+		d, done = it.Next()
+		require.Equal(t, time.Duration(0), d)
+		require.True(t, done)
+	}
+}
+
+func ExampleWithMaxDelay() {
+	it := WithMaxDelay(time.Second * 3)(Exponential(time.Second)).Iterator()
+	for i := 0; i < 4; i++ {
+		d, done := it.Next()
+		fmt.Printf("#%v: { %v, %v }\n", i, d, done)
+	}
+	// Output:
+	// #0: { 1s, false }
+	// #1: { 2s, false }
+	// #2: { 3s, false }
+	// #3: { 3s, false }
+}
+
+func TestWithMinDelay(t *testing.T) {
+	b := LinearRate(time.Second, -time.Second)
+	b = WithMaxRetries(3)(b)
+	b = WithMinDelay(time.Millisecond * 100)(b)
+	for i := 0; i < 3; i++ {
+		it := b.Iterator()
+		d, done := it.Next()
+		require.Equal(t, time.Second, d)
+		require.False(t, done)
+		d, done = it.Next()
+		require.Equal(t, time.Millisecond*100, d)
+		require.False(t, done)
+		d, done = it.Next()
+		require.Equal(t, time.Millisecond*100, d)
+		require.False(t, done)
+		d, done = it.Next()
+		require.Equal(t, time.Duration(0), d)
+		require.True(t, done)
+	}
+}
+
 func TestWithJitter(t *testing.T) {
 	b := Linear(time.Second)
 	b = WithMaxRetries(3)(b)
@@ -294,3 +449,146 @@ func TestWithJitter(t *testing.T) {
 	require.Equal(t, time.Duration(0), d)
 	require.False(t, done)
 }
+
+func TestWithJitterSource(t *testing.T) {
+	b := Constant(time.Second)
+	b = WithJitterSource(time.Millisecond*100, rand.NewSource(42))(b)
+	r := rand.New(rand.NewSource(42))
+	it := b.Iterator()
+	for i := 0; i < 3; i++ {
+		d, done := it.Next()
+		require.Equal(t, time.Second+time.Duration(r.Int63n(int64(time.Millisecond*200)+1)-int64(time.Millisecond*100)), d)
+		require.False(t, done)
+	}
+}
+
+func TestWithJitterConcurrent(t *testing.T) {
+	b := WithJitter(time.Millisecond * 100)(Constant(time.Second))
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			it := b.Iterator()
+			for j := 0; j < 100; j++ {
+				if d, _ := it.Next(); d < time.Millisecond*900 || d > time.Millisecond*1100 {
+					t.Errorf("unexpected delay %v", d)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestWithFullJitter(t *testing.T) {
+	b := Linear(time.Second)
+	b = WithMaxRetries(3)(b)
+	b = WithFullJitterSource(rand.NewSource(42))(b)
+	r := rand.New(rand.NewSource(42))
+	for i := 0; i < 3; i++ {
+		it := b.Iterator()
+		for j := 1; j <= 3; j++ {
+			d, done := it.Next()
+			require.Equal(t, time.Duration(r.Int63n(int64(time.Second)*int64(j)+1)), d)
+			require.False(t, done)
+		}
+		d, done := it.Next()
+		require.Equal(t, time.Duration(0), d)
+		require.True(t, done)
+	}
+
+	// for test coverage
+	it := WithFullJitter()(Constant(-time.Second)).Iterator()
+	d, done := it.Next()
+	require.Equal(t, time.Duration(0), d)
+	require.False(t, done)
+}
+
+func TestWithEqualJitter(t *testing.T) {
+	b := Linear(time.Second)
+	b = WithMaxRetries(3)(b)
+	b = WithEqualJitterSource(rand.NewSource(42))(b)
+	r := rand.New(rand.NewSource(42))
+	for i := 0; i < 3; i++ {
+		it := b.Iterator()
+		for j := 1; j <= 3; j++ {
+			h := time.Millisecond * 500 * time.Duration(j)
+			d, done := it.Next()
+			require.Equal(t, h+time.Duration(r.Int63n(int64(h)+1)), d)
+			require.False(t, done)
+		}
+		d, done := it.Next()
+		require.Equal(t, time.Duration(0), d)
+		require.True(t, done)
+	}
+
+	// for test coverage
+	it := WithEqualJitter()(Constant(-time.Second)).Iterator()
+	d, done := it.Next()
+	require.Equal(t, time.Duration(0), d)
+	require.False(t, done)
+}
+
+func TestWithDecorrelatedJitter(t *testing.T) {
+	b := Constant(time.Second)
+	b = WithMaxRetries(10)(b)
+	b = WithDecorrelatedJitterSource(time.Second*10, rand.NewSource(42))(b)
+	r := rand.New(rand.NewSource(42))
+	for i := 0; i < 3; i++ {
+		it := b.Iterator()
+		prev := time.Second
+		for j := 0; j < 10; j++ {
+			d, done := it.Next()
+			v := time.Second + time.Duration(r.Int63n(int64(prev*3-time.Second)+1))
+			if v > time.Second*10 {
+				v = time.Second * 10
+			}
+			require.Equal(t, v, d)
+			require.False(t, done)
+			prev = d
+		}
+		d, done := it.Next()
+		require.Equal(t, time.Duration(0), d)
+		require.True(t, done)
+	}
+
+	// for test coverage
+	it := WithDecorrelatedJitter(0)(Constant(time.Duration(math.MaxInt64))).Iterator()
+	d, done := it.Next()
+	require.Equal(t, time.Duration(math.MaxInt64), d)
+	require.False(t, done)
+	it = WithDecorrelatedJitter(0)(Constant(-time.Second)).Iterator()
+	d, done = it.Next()
+	require.Equal(t, time.Duration(0), d)
+	require.False(t, done)
+}
+
+func TestWithJitterFactor(t *testing.T) {
+	b := Exponential(time.Second)
+	b = WithMaxRetries(3)(b)
+	b = WithJitterFactorSource(0.2, rand.NewSource(42))(b)
+	r := rand.New(rand.NewSource(42))
+	for i := 0; i < 3; i++ {
+		it := b.Iterator()
+		for _, v := range []time.Duration{time.Second, time.Second * 2, time.Second * 4} {
+			d, done := it.Next()
+			require.Equal(t, time.Duration(float64(v)*(1+0.2*(2*r.Float64()-1))), d)
+			require.False(t, done)
+		}
+		d, done := it.Next()
+		require.Equal(t, time.Duration(0), d)
+		require.True(t, done)
+	}
+
+	// for test coverage
+	it := WithJitterFactor(0.2)(Constant(-time.Second)).Iterator()
+	d, done := it.Next()
+	require.Equal(t, time.Duration(0), d)
+	require.False(t, done)
+	it = WithJitterFactor(2)(Constant(time.Duration(math.MaxInt64))).Iterator()
+	for j := 0; j < 10; j++ {
+		d, done = it.Next()
+		require.True(t, 0 <= d)
+		require.False(t, done)
+	}
+}
@@ -3,12 +3,38 @@ package trier
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
 // Trier defines parameters for executing retriable functions.
 type Trier struct {
-	b Iterable
+	b           Iterable
+	deadline    DeadlinePolicy
+	classifiers []Classifier
+	before      func(ctx context.Context, attempt int)
+	retry       func(ctx context.Context, attempt int, err error, d time.Duration)
+	giveUp      func(ctx context.Context, attempts int, err error)
+	timeout     time.Duration
+	clock       Clock
+	retryAfter  RetryAfterPolicy
+	breaker     *Breaker
+	budget      Budget
+}
+
+// Option configures trier.
+type Option interface {
+	apply(*Trier)
+}
+
+type optionFunc func(*Trier)
+
+func (fn optionFunc) apply(t *Trier) {
+	fn(t)
+}
+
+func (fn Decorator) apply(t *Trier) {
+	t.b = fn(t.b)
 }
 
 // NewTrier creates new trier.
@@ -16,41 +42,239 @@ func NewTrier(b Iterable, fns ...Decorator) Trier {
 	for _, fn := range fns {
 		b = fn(b)
 	}
-	return Trier{b}
+	return Trier{b: b}
 }
 
-// Retriable is a function which execution could be retried, returns execution success flag.
+// NewTrierWithOptions creates new trier, decorators are options too.
+func NewTrierWithOptions(b Iterable, options ...Option) Trier {
+	t := Trier{b: b}
+	for _, o := range options {
+		o.apply(&t)
+	}
+	return t
+}
+
+// DeadlinePolicy defines behavior of trier when delay between retries exceeds context deadline,
+// time left before the deadline is measured using real time because context uses it,
+// the duration of the previous attempt is measured using the clock of trier.
+type DeadlinePolicy int
+
+const (
+	// DeadlineIgnore waits for context deadline, this is the default.
+	DeadlineIgnore DeadlinePolicy = iota
+	// DeadlineFail returns ErrDeadline without waiting.
+	DeadlineFail
+	// DeadlineShorten makes one last attempt without delay if it could finish before context deadline,
+	// the duration of the attempt is estimated by the duration of the previous attempt.
+	DeadlineShorten
+)
+
+// WithDeadlinePolicy sets behavior of trier when delay between retries exceeds context deadline.
+func WithDeadlinePolicy(p DeadlinePolicy) Option {
+	return optionFunc(func(t *Trier) {
+		t.deadline = p
+	})
+}
+
+// RetryAfterPolicy defines how trier uses delay suggested by RetryAfterError.
+type RetryAfterPolicy int
+
+const (
+	// RetryAfterMin uses suggested delay as minimum over delay created by iterator, this is the default.
+	RetryAfterMin RetryAfterPolicy = iota
+	// RetryAfterReplace uses suggested delay instead of delay created by iterator.
+	RetryAfterReplace
+)
+
+// WithRetryAfterPolicy sets how trier uses delay suggested by RetryAfterError.
+func WithRetryAfterPolicy(p RetryAfterPolicy) Option {
+	return optionFunc(func(t *Trier) {
+		t.retryAfter = p
+	})
+}
+
+// Retriable is a function which execution could be retried, returns execution success flag,
+// error wrapped using Permanent signals that execution will never succeed.
 type Retriable func(ctx context.Context) (bool, error)
 
-// Try executes retriable function, retries execution if execution success flag equals false.
+// Try executes retriable function, retries execution if execution success flag equals false,
+// returns the error of retriable function as is, so PermanentError could be detected using IsPermanent.
 func (t Trier) Try(ctx context.Context, fn Retriable) (bool, error) {
+	err := t.Run(ctx, fn)
+	if err == nil {
+		return true, nil
+	}
+	if e, ok := err.(*ExhaustedError); ok {
+		return false, e.Err
+	}
+	return false, err
+}
+
+// Run executes retriable function, retries execution if execution success flag equals false,
+// returns ExhaustedError if iterator is exhausted.
+func (t Trier) Run(ctx context.Context, fn Retriable) error {
+	return t.run(ctx, fn, false)
+}
+
+// Operation is a function which execution could be retried, returns nil error on success.
+type Operation func(ctx context.Context) error
+
+// Retry executes operation, retries execution if operation returns error which is not permanent,
+// returns ExhaustedError wrapping the last error if iterator is exhausted.
+func (t Trier) Retry(ctx context.Context, op Operation) error {
+	return t.run(ctx, func(ctx context.Context) (bool, error) {
+		err := op(ctx)
+		return err == nil, err
+	}, true)
+}
+
+// Do executes function, retries execution if function returns error which is not permanent,
+// returns the value and the error of the last execution, the error is ExhaustedError if iterator is exhausted.
+func Do[T any](ctx context.Context, t Trier, fn func(ctx context.Context) (T, error)) (T, error) {
+	var v T
+	err := t.Retry(ctx, func(ctx context.Context) error {
+		var err error
+		v, err = fn(ctx)
+		return err
+	})
+	return v, err
+}
+
+func (t Trier) run(ctx context.Context, fn Retriable, retryErr bool) error {
+	attempts, err := t.loop(ctx, fn, retryErr)
+	if err != nil && t.giveUp != nil {
+		t.giveUp(ctx, attempts, err)
+	}
+	return err
+}
+
+func (t Trier) loop(ctx context.Context, fn Retriable, retryErr bool) (int, error) {
+	c := t.clock
+	if c == nil {
+		c = clock{}
+	}
 	var it Iterator
-	var timer *time.Timer
-	for {
-		ok, err := fn(ctx)
-		if err != nil {
-			return false, err
+	var timer Timer
+	var shortened bool
+	var d time.Duration
+	begin := c.Now()
+	start := begin
+	for attempts := 1; ; attempts++ {
+		var gen uint64
+		if t.breaker != nil {
+			var err error
+			if gen, err = t.breaker.Allow(); err != nil {
+				return attempts - 1, err
+			}
+		}
+		actx := withAttempt(ctx, Attempt{attempts, start.Sub(begin), d})
+		if t.before != nil {
+			t.before(actx, attempts)
 		}
-		if ok {
-			return ok, err
+		ok, timedOut, err := t.attempt(actx, fn)
+		if t.breaker != nil {
+			// Execution stopped by the caller says nothing about the dependency.
+			t.breaker.report(ctx, gen, ok, err)
+		}
+		var ra *RetryAfterError
+		if err != nil {
+			if IsPermanent(err) {
+				return attempts, err
+			}
+			errors.As(err, &ra)
+			a := ActionRetry
+			if !timedOut {
+				a = t.classify(err)
+			}
+			if a == ActionDefault {
+				if retryErr || ra != nil {
+					a = ActionRetry
+				} else {
+					a = ActionAbort
+				}
+			}
+			switch a {
+			case ActionAbort:
+				return attempts, err
+			case ActionStop:
+				return attempts, &ExhaustedError{Attempts: attempts, Elapsed: c.Now().Sub(begin), Err: err}
+			}
+			if ctx.Err() != nil {
+				return attempts, ctx.Err()
+			}
+		} else if ok {
+			if t.budget != nil {
+				t.budget.Deposit()
+			}
+			return attempts, nil
 		}
 		if it == nil {
 			it = t.b.Iterator()
 		}
-		d, done := it.Next()
+		var done bool
+		d, done = it.Next()
 		if done {
-			return false, err
+			return attempts, &ExhaustedError{Attempts: attempts, Elapsed: c.Now().Sub(begin), Err: err}
+		}
+		if ra != nil {
+			if t.retryAfter == RetryAfterReplace || d < ra.Delay {
+				d = ra.Delay
+			}
+		}
+		if t.deadline != DeadlineIgnore {
+			if deadline, ok := ctx.Deadline(); ok {
+				// Context deadline is measured using real time whatever the clock is.
+				left := time.Until(deadline)
+				if d > left {
+					if t.deadline == DeadlineFail || shortened {
+						return attempts, ErrDeadline
+					}
+					if c.Now().Sub(start) > left {
+						return attempts, ErrDeadline
+					}
+					shortened = true
+					d = 0
+				}
+			}
+		}
+		if t.budget != nil && !t.budget.Withdraw() {
+			return attempts, ErrBudgetExhausted
+		}
+		if t.retry != nil {
+			t.retry(actx, attempts, err, d)
 		}
 		if timer == nil {
-			timer = time.NewTimer(d)
+			timer = c.NewTimer(d)
 			defer timer.Stop()
 		} else {
 			timer.Reset(d)
 		}
 		select {
 		case <-ctx.Done():
-			return false, ctx.Err()
-		case <-timer.C:
+			return attempts, ctx.Err()
+		case <-timer.C():
 		}
+		start = c.Now()
+	}
+}
+
+// WithAttemptTimeout sets timeout of each execution of retriable function,
+// execution which exceeds the timeout is retried unless context passed to trier is done.
+func WithAttemptTimeout(d time.Duration) Option {
+	return optionFunc(func(t *Trier) {
+		t.timeout = d
+	})
+}
+
+// attempt executes retriable function, reports whether the execution exceeded the attempt timeout.
+func (t Trier) attempt(ctx context.Context, fn Retriable) (bool, bool, error) {
+	if t.timeout <= 0 {
+		ok, err := fn(ctx)
+		return ok, false, err
 	}
+	actx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+	ok, err := fn(actx)
+	timedOut := err != nil && errors.Is(err, context.DeadlineExceeded) && actx.Err() != nil && ctx.Err() == nil
+	return ok, timedOut, err
 }
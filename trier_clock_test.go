@@ -0,0 +1,250 @@
+package trier_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/da440dil/go-trier"
+	"github.com/da440dil/go-trier/triertest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrierWithDeadlinePolicy(t *testing.T) {
+	b := Constant(time.Millisecond * 100)
+	fn := func(ctx context.Context) (bool, error) {
+		return false, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+
+	c := triertest.NewAutoClock(time.Unix(0, 0))
+	tr := NewTrierWithOptions(b, WithDeadlinePolicy(DeadlineFail), WithClock(c))
+	ok, err := tr.Try(ctx, fn)
+	require.Equal(t, ErrDeadline, err)
+	require.False(t, ok)
+	require.Empty(t, c.Delays())
+
+	tr = NewTrierWithOptions(Constant(time.Hour), WithMaxRetries(3), WithDeadlinePolicy(DeadlineFail), WithClock(c))
+	ok, err = tr.Try(ctx, fn)
+	require.Equal(t, ErrDeadline, err)
+	require.False(t, ok)
+	require.Empty(t, c.Delays())
+
+	n := 0
+	tr = NewTrierWithOptions(b, WithDeadlinePolicy(DeadlineShorten), WithClock(c))
+	ok, err = tr.Try(ctx, func(ctx context.Context) (bool, error) {
+		n++
+		return false, nil
+	})
+	require.Equal(t, ErrDeadline, err)
+	require.False(t, ok)
+	require.Equal(t, 2, n)
+	require.NoError(t, ctx.Err())
+	require.Equal(t, []time.Duration{0}, c.Delays())
+
+	c = triertest.NewAutoClock(time.Unix(0, 0))
+	tr = NewTrierWithOptions(b, WithDeadlinePolicy(DeadlineShorten), WithClock(c))
+	ok, err = tr.Try(ctx, func(ctx context.Context) (bool, error) {
+		c.Advance(time.Millisecond * 60)
+		return false, nil
+	})
+	require.Equal(t, ErrDeadline, err)
+	require.False(t, ok)
+	require.Empty(t, c.Delays())
+}
+
+func TestTrierRun(t *testing.T) {
+	b := WithMaxRetries(2)(Constant(time.Millisecond))
+	c := triertest.NewAutoClock(time.Unix(0, 0))
+	tr := NewTrierWithOptions(b, WithClock(c))
+	var ok bool
+	fn := func(ctx context.Context) (bool, error) {
+		return ok, nil
+	}
+	ctx := context.Background()
+
+	err := tr.Run(ctx, fn)
+	require.True(t, errors.Is(err, ErrExhausted))
+	var e *ExhaustedError
+	require.True(t, errors.As(err, &e))
+	require.Equal(t, 3, e.Attempts)
+	require.Equal(t, time.Millisecond*2, e.Elapsed)
+	require.NoError(t, e.Err)
+	require.Equal(t, "trier: retries exhausted after 3 attempts in 2ms", err.Error())
+
+	ok = true
+	require.NoError(t, tr.Run(ctx, fn))
+
+	x := errors.New("some error")
+	e = &ExhaustedError{Attempts: 1, Elapsed: time.Second, Err: x}
+	require.True(t, errors.Is(e, ErrExhausted))
+	require.True(t, errors.Is(e, x))
+	require.Equal(t, "trier: retries exhausted after 1 attempts in 1s: some error", e.Error())
+}
+
+func TestTrierRetry(t *testing.T) {
+	b := WithMaxRetries(2)(Constant(time.Millisecond))
+	c := triertest.NewAutoClock(time.Unix(0, 0))
+	tr := NewTrierWithOptions(b, WithClock(c))
+	ctx := context.Background()
+	e := errors.New("some error")
+
+	n := 0
+	err := tr.Retry(ctx, func(ctx context.Context) error {
+		n++
+		return e
+	})
+	require.True(t, errors.Is(err, ErrExhausted))
+	require.True(t, errors.Is(err, e))
+	require.Equal(t, 3, n)
+	require.Equal(t, []time.Duration{time.Millisecond, time.Millisecond}, c.Delays())
+
+	n = 0
+	err = tr.Retry(ctx, func(ctx context.Context) error {
+		n++
+		if n < 3 {
+			return e
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+
+	n = 0
+	err = tr.Retry(ctx, func(ctx context.Context) error {
+		n++
+		return Permanent(e)
+	})
+	require.True(t, errors.Is(err, e))
+	require.False(t, errors.Is(err, ErrExhausted))
+	require.Equal(t, 1, n)
+
+	ctx, cancel := context.WithCancel(ctx)
+	n = 0
+	err = tr.Retry(ctx, func(ctx context.Context) error {
+		n++
+		cancel()
+		return ctx.Err()
+	})
+	require.Equal(t, context.Canceled, err)
+	require.Equal(t, 1, n)
+}
+
+func TestTrierPermanent(t *testing.T) {
+	tr := NewTrierWithOptions(Constant(time.Millisecond), WithClock(triertest.NewAutoClock(time.Unix(0, 0))))
+	e := errors.New("some error")
+
+	ok, err := tr.Try(context.Background(), func(ctx context.Context) (bool, error) {
+		return false, Permanent(e)
+	})
+	require.False(t, ok)
+	require.True(t, IsPermanent(err))
+	require.True(t, errors.Is(err, e))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	ok, err = tr.Try(ctx, func(ctx context.Context) (bool, error) {
+		return false, nil
+	})
+	require.False(t, ok)
+	require.False(t, IsPermanent(err))
+	require.Equal(t, context.Canceled, err)
+}
+
+func TestDo(t *testing.T) {
+	tr := NewTrierWithOptions(Constant(time.Millisecond), WithMaxRetries(2), WithClock(triertest.NewAutoClock(time.Unix(0, 0))))
+	ctx := context.Background()
+	e := errors.New("some error")
+
+	n := 0
+	v, err := Do(ctx, tr, func(ctx context.Context) (int, error) {
+		n++
+		if n < 3 {
+			return 0, e
+		}
+		return n, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, v)
+
+	s, err := Do(ctx, tr, func(ctx context.Context) (string, error) {
+		return "", e
+	})
+	require.True(t, errors.Is(err, ErrExhausted))
+	require.True(t, errors.Is(err, e))
+	require.Equal(t, "", s)
+}
+
+func TestTrierWithAttemptTimeout(t *testing.T) {
+	tr := NewTrierWithOptions(Constant(time.Millisecond), WithMaxRetries(2), WithAttemptTimeout(time.Millisecond*10))
+	ctx := context.Background()
+
+	n := 0
+	ok, err := tr.Try(ctx, func(ctx context.Context) (bool, error) {
+		n++
+		if n < 3 {
+			<-ctx.Done()
+			return false, ctx.Err()
+		}
+		_, ok := ctx.Deadline()
+		return ok, nil
+	})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, 3, n)
+
+	err = tr.Retry(ctx, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	require.True(t, errors.Is(err, ErrExhausted))
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	ctx, cancel := context.WithTimeout(ctx, time.Millisecond*5)
+	defer cancel()
+	n = 0
+	ok, err = tr.Try(ctx, func(ctx context.Context) (bool, error) {
+		n++
+		<-ctx.Done()
+		return false, ctx.Err()
+	})
+	require.Equal(t, context.DeadlineExceeded, err)
+	require.False(t, ok)
+	require.Equal(t, 1, n)
+}
+
+func TestTrierRetryAfter(t *testing.T) {
+	b := LinearRate(time.Millisecond*10, -time.Millisecond*10)
+	e := errors.New("some error")
+	n := 0
+	fn := func(ctx context.Context) (bool, error) {
+		n++
+		if n < 3 {
+			return false, RetryAfter(e, time.Millisecond*5)
+		}
+		return true, nil
+	}
+
+	c := triertest.NewAutoClock(time.Unix(0, 0))
+	ok, err := NewTrierWithOptions(b, WithClock(c)).Try(context.Background(), fn)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []time.Duration{time.Millisecond * 10, time.Millisecond * 5}, c.Delays())
+
+	c = triertest.NewAutoClock(time.Unix(0, 0))
+	n = 0
+	ok, err = NewTrierWithOptions(b, WithRetryAfterPolicy(RetryAfterReplace), WithClock(c)).Try(context.Background(), fn)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []time.Duration{time.Millisecond * 5, time.Millisecond * 5}, c.Delays())
+
+	n = 0
+	ok, err = NewTrierWithOptions(b, WithClassifier(func(err error) Action {
+		return ActionAbort
+	})).Try(context.Background(), fn)
+	require.False(t, ok)
+	require.True(t, errors.Is(err, e))
+	require.Equal(t, 1, n)
+}
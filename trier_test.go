@@ -33,7 +33,7 @@ func (m *trymock) Try(ctx context.Context) (bool, error) {
 
 func TestTrier(t *testing.T) {
 	b := &imock{0, true}
-	tr := Trier{b}
+	tr := Trier{b: b}
 
 	e := errors.New("some error")
 	f := &trymock{ok: false, err: e}
@@ -0,0 +1,188 @@
+// Package triergrpc provides gRPC client interceptors which retry calls using trier.
+package triergrpc
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/da440dil/go-trier"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// AttemptHeader is the name of outgoing header which contains attempt number, starts from 1.
+const AttemptHeader = "x-retry-attempt"
+
+// DefaultCodes are status codes which are retried by default.
+var DefaultCodes = []codes.Code{codes.Unavailable}
+
+type options struct {
+	codes map[codes.Code]bool
+}
+
+// Option configures interceptor.
+type Option func(*options)
+
+// WithCodes sets status codes which are retried.
+func WithCodes(cs ...codes.Code) Option {
+	return func(o *options) {
+		o.codes = make(map[codes.Code]bool, len(cs))
+		for _, c := range cs {
+			o.codes[c] = true
+		}
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	WithCodes(DefaultCodes...)(o)
+	for _, fn := range opts {
+		fn(o)
+	}
+	return o
+}
+
+// UnaryClientInterceptor creates interceptor which retries unary calls on configured status codes,
+// each attempt is limited by the deadline of the call.
+func UnaryClientInterceptor(tr trier.Trier, opts ...Option) grpc.UnaryClientInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		err := tr.Retry(ctx, func(ctx context.Context) error {
+			return o.check(invoker(withAttempt(ctx), method, req, reply, cc, callOpts...))
+		})
+		return unwrap(err)
+	}
+}
+
+// StreamClientInterceptor creates interceptor which retries creation of streams on configured status codes.
+// Server streams are also recreated and the request message is resent until the first response message
+// is received, messages of other streams are never retried.
+func StreamClientInterceptor(tr trier.Trier, opts ...Option) grpc.StreamClientInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		s := &clientStream{ctx: ctx, tr: tr, o: o, create: func(ctx context.Context) (grpc.ClientStream, error) {
+			return streamer(ctx, desc, cc, method, callOpts...)
+		}}
+		err := tr.Retry(ctx, func(context.Context) error {
+			return s.open()
+		})
+		if err != nil {
+			return nil, unwrap(err)
+		}
+		if desc.ClientStreams {
+			return s.ClientStream, nil
+		}
+		return s, nil
+	}
+}
+
+// clientStream is server stream which is recreated until the first response message is received.
+type clientStream struct {
+	grpc.ClientStream
+	ctx      context.Context
+	tr       trier.Trier
+	o        *options
+	create   func(ctx context.Context) (grpc.ClientStream, error)
+	attempts int
+	req      interface{}
+	closed   bool
+	received bool
+}
+
+// open creates new stream, the stream outlives the attempt, so it uses the context of the call.
+func (s *clientStream) open() error {
+	s.attempts++
+	ctx := metadata.AppendToOutgoingContext(s.ctx, AttemptHeader, strconv.Itoa(s.attempts))
+	cs, err := s.create(ctx)
+	if err != nil {
+		return s.o.check(err)
+	}
+	s.ClientStream = cs
+	return nil
+}
+
+func (s *clientStream) SendMsg(m interface{}) error {
+	s.req = m
+	return s.ClientStream.SendMsg(m)
+}
+
+func (s *clientStream) CloseSend() error {
+	s.closed = true
+	return s.ClientStream.CloseSend()
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	if s.received {
+		return s.ClientStream.RecvMsg(m)
+	}
+	first := true
+	err := s.tr.Retry(s.ctx, func(context.Context) error {
+		if !first {
+			if err := s.reopen(); err != nil {
+				return err
+			}
+		}
+		first = false
+		return s.o.check(s.ClientStream.RecvMsg(m))
+	})
+	if err != nil {
+		return unwrap(err)
+	}
+	s.received = true
+	return nil
+}
+
+// reopen creates new stream and resends the request message.
+func (s *clientStream) reopen() error {
+	if err := s.open(); err != nil {
+		return err
+	}
+	if s.req != nil {
+		if err := s.ClientStream.SendMsg(s.req); err != nil {
+			return s.o.check(err)
+		}
+	}
+	if s.closed {
+		if err := s.ClientStream.CloseSend(); err != nil {
+			return s.o.check(err)
+		}
+	}
+	return nil
+}
+
+// check marks error as permanent unless its status code is retried.
+func (o *options) check(err error) error {
+	if err == nil || o.codes[status.Code(err)] {
+		return err
+	}
+	return trier.Permanent(err)
+}
+
+// withAttempt appends attempt number stored in context to outgoing metadata of the context.
+func withAttempt(ctx context.Context) context.Context {
+	a, ok := trier.AttemptFromContext(ctx)
+	if !ok {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, AttemptHeader, strconv.Itoa(a.Number))
+}
+
+// unwrap returns the status error of the last attempt or converts context error to status error,
+// so status code could be read using status.Code.
+func unwrap(err error) error {
+	var p *trier.PermanentError
+	if errors.As(err, &p) {
+		return p.Err
+	}
+	var e *trier.ExhaustedError
+	if errors.As(err, &e) && e.Err != nil {
+		return e.Err
+	}
+	if err == context.Canceled || err == context.DeadlineExceeded {
+		return status.FromContextError(err).Err()
+	}
+	return err
+}
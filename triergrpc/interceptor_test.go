@@ -0,0 +1,155 @@
+package triergrpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/da440dil/go-trier"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type server struct {
+	healthpb.UnimplementedHealthServer
+	mu       sync.Mutex
+	errs     []error
+	attempts []string
+}
+
+func (s *server) next(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	md, _ := metadata.FromIncomingContext(ctx)
+	s.attempts = append(s.attempts, md.Get(AttemptHeader)...)
+	if len(s.errs) == 0 {
+		return nil
+	}
+	err := s.errs[0]
+	s.errs = s.errs[1:]
+	return err
+}
+
+func (s *server) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	if err := s.next(ctx); err != nil {
+		return nil, err
+	}
+	return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+}
+
+func (s *server) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	if err := s.next(stream.Context()); err != nil {
+		return err
+	}
+	return stream.Send(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING})
+}
+
+func newClient(t *testing.T, srv *server, opts ...Option) healthpb.HealthClient {
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	healthpb.RegisterHealthServer(s, srv)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	tr := trier.NewTrier(trier.Constant(time.Millisecond), trier.WithMaxRetries(2))
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor(tr, opts...)),
+		grpc.WithStreamInterceptor(StreamClientInterceptor(tr, opts...)),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return healthpb.NewHealthClient(conn)
+}
+
+func unavailable() error {
+	return status.Error(codes.Unavailable, "unavailable")
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	srv := &server{errs: []error{unavailable(), unavailable()}}
+	c := newClient(t, srv)
+	res, err := c.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, res.Status)
+	require.Equal(t, []string{"1", "2", "3"}, srv.attempts)
+
+	srv.attempts = nil
+	srv.errs = []error{unavailable(), unavailable(), unavailable()}
+	_, err = c.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	require.Equal(t, []string{"1", "2", "3"}, srv.attempts)
+
+	srv.attempts = nil
+	srv.errs = []error{status.Error(codes.InvalidArgument, "invalid argument")}
+	_, err = c.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Equal(t, []string{"1"}, srv.attempts)
+}
+
+func TestUnaryClientInterceptorWithCodes(t *testing.T) {
+	srv := &server{errs: []error{status.Error(codes.Aborted, "aborted"), unavailable()}}
+	c := newClient(t, srv, WithCodes(codes.Aborted))
+	_, err := c.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	require.Equal(t, []string{"1", "2"}, srv.attempts)
+}
+
+func TestUnaryClientInterceptorDeadline(t *testing.T) {
+	srv := &server{errs: []error{unavailable(), unavailable()}}
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	healthpb.RegisterHealthServer(s, srv)
+	go s.Serve(lis)
+	defer s.Stop()
+
+	tr := trier.NewTrier(trier.Constant(time.Second))
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor(tr)),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	require.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	require.Equal(t, []string{"1"}, srv.attempts)
+}
+
+func TestStreamClientInterceptor(t *testing.T) {
+	srv := &server{errs: []error{unavailable()}}
+	c := newClient(t, srv)
+	stream, err := c.Watch(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	res, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, res.Status)
+	_, err = stream.Recv()
+	require.Equal(t, io.EOF, err)
+	require.Equal(t, []string{"1", "2"}, srv.attempts)
+
+	srv.attempts = nil
+	srv.errs = []error{unavailable(), unavailable(), unavailable()}
+	stream, err = c.Watch(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	require.Equal(t, []string{"1", "2", "3"}, srv.attempts)
+}
@@ -0,0 +1,246 @@
+// Package trierhttp provides http.RoundTripper which retries requests using trier.
+package trierhttp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/da440dil/go-trier"
+)
+
+// DefaultStatusCodes are response status codes which are retried by default.
+var DefaultStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// maxBuffer is the maximum number of bytes of body of response with retried status code kept in memory,
+// the body is read before the next attempt to let the connection be reused.
+const maxBuffer = 64 << 10
+
+// ErrBodyTruncated is the error returned reading body of response with retried status code
+// if the body exceeded the maximum number of bytes kept in memory.
+var ErrBodyTruncated = errors.New("trierhttp: response body truncated")
+
+// StatusError is the error returned to trier when response status code is retried.
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("trierhttp: unexpected status code %v", e.StatusCode)
+}
+
+// Transport is http.RoundTripper which retries idempotent requests
+// on errors of the underlying round tripper and on configured response status codes.
+type Transport struct {
+	base  http.RoundTripper
+	trier trier.Trier
+	codes map[int]bool
+}
+
+// Option configures transport.
+type Option func(*Transport)
+
+// WithStatusCodes sets response status codes which are retried.
+func WithStatusCodes(codes ...int) Option {
+	return func(t *Transport) {
+		t.codes = make(map[int]bool, len(codes))
+		for _, code := range codes {
+			t.codes[code] = true
+		}
+	}
+}
+
+// NewTransport creates new transport, uses http.DefaultTransport if base round tripper is nil.
+func NewTransport(base http.RoundTripper, tr trier.Trier, options ...Option) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	t := &Transport{base: base, trier: tr}
+	WithStatusCodes(DefaultStatusCodes...)(t)
+	for _, o := range options {
+		o(t)
+	}
+	return t
+}
+
+// RoundTrip executes request, retries execution if request is idempotent and its body could be rewound.
+// The first attempt uses the request body, retries use its copy returned by GetBody.
+// Context of request of each attempt carries values of the attempt context, e.g. Attempt,
+// and is cancelled if the attempt context is done before the response is received,
+// so attempt timeout does not cut off body of the returned response.
+// If trier gives up after response with retried status code the response is returned,
+// its body is buffered in memory up to 64 KiB.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !retriable(req) {
+		return t.base.RoundTrip(req)
+	}
+	ctx := req.Context()
+	var res *http.Response
+	first := true
+	err := t.trier.Retry(ctx, func(actx context.Context) error {
+		res = nil
+		r := req.Clone(ctx)
+		if first {
+			first = false
+		} else if req.Body != nil && req.Body != http.NoBody {
+			body, err := req.GetBody()
+			if err != nil {
+				return trier.Permanent(err)
+			}
+			r.Body = body
+		}
+		var err error
+		res, err = t.roundTrip(ctx, actx, r)
+		if err != nil {
+			return err
+		}
+		if !t.codes[res.StatusCode] {
+			return nil
+		}
+		buffer(res)
+		err = &StatusError{res.StatusCode}
+		if d, ok := retryAfter(res.Header.Get("Retry-After")); ok {
+			err = trier.RetryAfter(err, d)
+		}
+		return err
+	})
+	if first && req.Body != nil {
+		req.Body.Close()
+	}
+	if res != nil && (err == nil || ctx.Err() == nil) {
+		return res, nil
+	}
+	return nil, err
+}
+
+// roundTrip executes request of attempt using context which is cancelled by the request context
+// or by the attempt context until the response is received, the context is released when the response body is closed.
+func (t *Transport) roundTrip(ctx, actx context.Context, r *http.Request) (*http.Response, error) {
+	rctx, cancel := context.WithCancel(attemptContext{ctx, actx})
+	stop, stopped := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(stopped)
+		select {
+		case <-actx.Done():
+			cancel()
+		case <-stop:
+		}
+	}()
+	res, err := t.base.RoundTrip(r.WithContext(rctx))
+	close(stop)
+	<-stopped
+	if rctx.Err() != nil && ctx.Err() == nil {
+		// The attempt context is done, so the response could not be read anyway.
+		if res != nil {
+			res.Body.Close()
+		}
+		cancel()
+		return nil, actx.Err()
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	res.Body = &cancelBody{res.Body, cancel}
+	return res, nil
+}
+
+// attemptContext is the context which carries values of the attempt context
+// and is done when the request context is done.
+type attemptContext struct {
+	context.Context
+	attempt context.Context
+}
+
+func (c attemptContext) Value(key interface{}) interface{} {
+	return c.attempt.Value(key)
+}
+
+// cancelBody is response body which releases the context of request when it is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// retriable reports whether request is idempotent and its body could be rewound.
+func retriable(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	_, ok := req.Header["Idempotency-Key"]
+	if !ok {
+		_, ok = req.Header["X-Idempotency-Key"]
+	}
+	return ok
+}
+
+// retryAfter parses value of Retry-After header.
+func retryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if n, err := strconv.Atoi(v); err == nil {
+		if n < 0 {
+			return 0, false
+		}
+		return time.Duration(n) * time.Second, true
+	}
+	t, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+	d := time.Until(t)
+	if d < 0 {
+		d = 0
+	}
+	return d, true
+}
+
+// buffer reads body of response into memory and closes it.
+func buffer(res *http.Response) {
+	b, err := io.ReadAll(io.LimitReader(res.Body, maxBuffer+1))
+	res.Body.Close()
+	if err == nil && len(b) > maxBuffer {
+		b, err = b[:maxBuffer], ErrBodyTruncated
+	}
+	res.Body = &bufferedBody{bytes.NewReader(b), err}
+}
+
+// bufferedBody is body of response read into memory,
+// it returns the error which occurred reading the original body after the read bytes.
+type bufferedBody struct {
+	r   *bytes.Reader
+	err error
+}
+
+func (b *bufferedBody) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	if err == io.EOF && b.err != nil {
+		err = b.err
+	}
+	return n, err
+}
+
+func (b *bufferedBody) Close() error {
+	return nil
+}
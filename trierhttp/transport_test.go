@@ -0,0 +1,299 @@
+package trierhttp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/da440dil/go-trier"
+	"github.com/da440dil/go-trier/triertest"
+	"github.com/stretchr/testify/require"
+)
+
+func newServer(codes ...int) (*httptest.Server, *int32) {
+	n := new(int32)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i := int(atomic.AddInt32(n, 1)) - 1
+		code := http.StatusOK
+		if i < len(codes) {
+			code = codes[i]
+		}
+		if code == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "3")
+		}
+		body, _ := io.ReadAll(r.Body)
+		w.WriteHeader(code)
+		w.Write(body)
+	}))
+	return srv, n
+}
+
+func newClient(options ...Option) *http.Client {
+	tr := trier.NewTrier(trier.Constant(time.Millisecond), trier.WithMaxRetries(2))
+	return &http.Client{Transport: NewTransport(nil, tr, options...)}
+}
+
+func TestTransport(t *testing.T) {
+	srv, n := newServer(http.StatusServiceUnavailable, http.StatusBadGateway)
+	defer srv.Close()
+
+	res, err := newClient().Get(srv.URL)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, int32(3), atomic.LoadInt32(n))
+}
+
+func TestTransportExhausted(t *testing.T) {
+	srv, n := newServer(http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodPut, srv.URL, strings.NewReader("some body"))
+	require.NoError(t, err)
+	res, err := newClient().Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.Equal(t, "some body", string(body))
+	require.Equal(t, int32(3), atomic.LoadInt32(n))
+}
+
+type body struct {
+	io.Reader
+	closed bool
+}
+
+func (b *body) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestTransportBody(t *testing.T) {
+	srv, n := newServer(http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+	defer srv.Close()
+
+	b := &body{Reader: strings.NewReader("some body")}
+	req, err := http.NewRequest(http.MethodPut, srv.URL, b)
+	require.NoError(t, err)
+	calls := 0
+	req.GetBody = func() (io.ReadCloser, error) {
+		calls++
+		return io.NopCloser(strings.NewReader("some body")), nil
+	}
+	res, err := newClient().Do(req)
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, int32(3), atomic.LoadInt32(n))
+	require.Equal(t, 2, calls)
+	require.True(t, b.closed)
+
+	br := trier.NewBreaker(trier.BreakerConsecutiveFailures(1))
+	gen, err := br.Allow()
+	require.NoError(t, err)
+	br.Done(gen, false, nil)
+	tr := trier.NewTrierWithOptions(trier.Constant(time.Millisecond), trier.WithBreaker(br))
+	b = &body{Reader: strings.NewReader("some body")}
+	req, err = http.NewRequest(http.MethodPut, srv.URL, b)
+	require.NoError(t, err)
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader("some body")), nil
+	}
+	res, err = NewTransport(nil, tr).RoundTrip(req)
+	require.Nil(t, res)
+	require.Equal(t, trier.ErrBreakerOpen, err)
+	require.True(t, b.closed)
+}
+
+func TestTransportBodyTruncated(t *testing.T) {
+	b := &body{Reader: strings.NewReader(strings.Repeat("x", maxBuffer+1))}
+	base := roundTripper(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: b}, nil
+	})
+	tr := trier.NewTrier(trier.Constant(time.Millisecond), trier.WithMaxRetries(0))
+	req, err := http.NewRequest(http.MethodGet, "http://localhost", nil)
+	require.NoError(t, err)
+	res, err := NewTransport(base, tr).RoundTrip(req)
+	require.NoError(t, err)
+	require.True(t, b.closed)
+	v, err := io.ReadAll(res.Body)
+	require.Equal(t, ErrBodyTruncated, err)
+	require.Equal(t, maxBuffer, len(v))
+}
+
+func TestTransportStatusCodes(t *testing.T) {
+	srv, n := newServer(http.StatusInternalServerError)
+	defer srv.Close()
+
+	res, err := newClient().Get(srv.URL)
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusInternalServerError, res.StatusCode)
+	require.Equal(t, int32(1), atomic.LoadInt32(n))
+
+	atomic.StoreInt32(n, 0)
+	res, err = newClient(WithStatusCodes(http.StatusInternalServerError)).Get(srv.URL)
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, int32(2), atomic.LoadInt32(n))
+}
+
+func TestTransportNotIdempotent(t *testing.T) {
+	srv, n := newServer(http.StatusServiceUnavailable)
+	defer srv.Close()
+
+	res, err := newClient().Post(srv.URL, "text/plain", strings.NewReader("some body"))
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+	require.Equal(t, int32(1), atomic.LoadInt32(n))
+
+	atomic.StoreInt32(n, 0)
+	req, err := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader("some body"))
+	require.NoError(t, err)
+	req.Header.Set("Idempotency-Key", "key")
+	res, err = newClient().Do(req)
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, int32(2), atomic.LoadInt32(n))
+
+	atomic.StoreInt32(n, 0)
+	req, err = http.NewRequest(http.MethodPut, srv.URL, io.NopCloser(strings.NewReader("some body")))
+	require.NoError(t, err)
+	res, err = newClient().Do(req)
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+	require.Equal(t, int32(1), atomic.LoadInt32(n))
+}
+
+func TestTransportRetryAfter(t *testing.T) {
+	srv, n := newServer(http.StatusTooManyRequests)
+	defer srv.Close()
+
+	c := triertest.NewAutoClock(time.Now())
+	tr := trier.NewTrierWithOptions(trier.Constant(time.Millisecond), trier.WithMaxRetries(2), trier.WithClock(c))
+	client := &http.Client{Transport: NewTransport(nil, tr)}
+	res, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, int32(2), atomic.LoadInt32(n))
+	require.Equal(t, []time.Duration{time.Second * 3}, c.Delays())
+}
+
+type roundTripper func(*http.Request) (*http.Response, error)
+
+func (fn roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
+
+func TestTransportError(t *testing.T) {
+	e := errors.New("connection reset")
+	n := 0
+	base := roundTripper(func(req *http.Request) (*http.Response, error) {
+		n++
+		return nil, e
+	})
+	tr := trier.NewTrier(trier.Constant(time.Millisecond), trier.WithMaxRetries(2))
+	req, err := http.NewRequest(http.MethodGet, "http://localhost", nil)
+	require.NoError(t, err)
+	res, err := NewTransport(base, tr).RoundTrip(req)
+	require.Nil(t, res)
+	require.True(t, errors.Is(err, e))
+	require.True(t, errors.Is(err, trier.ErrExhausted))
+	require.Equal(t, 3, n)
+
+	e = errors.New("get body error")
+	req, err = http.NewRequest(http.MethodPut, "http://localhost", strings.NewReader("some body"))
+	require.NoError(t, err)
+	req.GetBody = func() (io.ReadCloser, error) {
+		return nil, e
+	}
+	res, err = NewTransport(base, tr).RoundTrip(req)
+	require.Nil(t, res)
+	require.True(t, errors.Is(err, e))
+}
+
+func TestTransportAttempt(t *testing.T) {
+	var numbers []int
+	base := roundTripper(func(req *http.Request) (*http.Response, error) {
+		a, ok := trier.AttemptFromContext(req.Context())
+		require.True(t, ok)
+		numbers = append(numbers, a.Number)
+		return nil, errors.New("connection reset")
+	})
+	tr := trier.NewTrier(trier.Constant(time.Millisecond), trier.WithMaxRetries(2))
+	req, err := http.NewRequest(http.MethodGet, "http://localhost", nil)
+	require.NoError(t, err)
+	_, err = NewTransport(base, tr).RoundTrip(req)
+	require.True(t, errors.Is(err, trier.ErrExhausted))
+	require.Equal(t, []int{1, 2, 3}, numbers)
+}
+
+func TestTransportAttemptTimeout(t *testing.T) {
+	var n int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&n, 1) == 1 {
+			<-r.Context().Done()
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		time.Sleep(time.Millisecond * 20)
+		w.Write([]byte("some body"))
+	}))
+	defer srv.Close()
+
+	tr := trier.NewTrierWithOptions(trier.Constant(time.Millisecond), trier.WithMaxRetries(2), trier.WithAttemptTimeout(time.Millisecond*50))
+	res, err := (&http.Client{Transport: NewTransport(nil, tr)}).Get(srv.URL)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.Equal(t, "some body", string(body))
+	require.Equal(t, int32(2), atomic.LoadInt32(&n))
+}
+
+func TestTransportCancel(t *testing.T) {
+	srv, _ := newServer(http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+	tr := trier.NewTrier(trier.Constant(time.Second))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	res, err := NewTransport(nil, tr).RoundTrip(req)
+	require.Nil(t, res)
+	require.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestRetryAfter(t *testing.T) {
+	d, ok := retryAfter("")
+	require.False(t, ok)
+	d, ok = retryAfter("-1")
+	require.False(t, ok)
+	d, ok = retryAfter("invalid")
+	require.False(t, ok)
+	d, ok = retryAfter("120")
+	require.True(t, ok)
+	require.Equal(t, time.Minute*2, d)
+	d, ok = retryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	require.True(t, ok)
+	require.True(t, time.Minute*59 < d && d <= time.Hour)
+	d, ok = retryAfter(time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat))
+	require.True(t, ok)
+	require.Equal(t, time.Duration(0), d)
+}
@@ -0,0 +1,103 @@
+// Package triersql provides helpers for retrying database/sql transactions using trier.
+package triersql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/da440dil/go-trier"
+)
+
+// DefaultSQLStates are SQLSTATE codes of serialization failure and deadlock.
+var DefaultSQLStates = []string{"40001", "40P01"}
+
+// TxBeginner starts transactions, implemented by *sql.DB and *sql.Conn.
+type TxBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+type options struct {
+	tx         *sql.TxOptions
+	classifier trier.Classifier
+}
+
+// Option configures transaction.
+type Option func(*options)
+
+// WithTxOptions sets options of transaction.
+func WithTxOptions(tx *sql.TxOptions) Option {
+	return func(o *options) {
+		o.tx = tx
+	}
+}
+
+// WithClassifier sets classifier of transaction errors, transaction is retried only on ActionRetry,
+// on any other action the error is returned without retries.
+func WithClassifier(c trier.Classifier) Option {
+	return func(o *options) {
+		o.classifier = c
+	}
+}
+
+// ClassifySQLState retries on errors which SQLState method returns one of the codes,
+// e.g. errors of github.com/lib/pq and github.com/jackc/pgx drivers.
+func ClassifySQLState(codes ...string) trier.Classifier {
+	return func(err error) trier.Action {
+		var e interface{ SQLState() string }
+		if errors.As(err, &e) {
+			state := e.SQLState()
+			for _, code := range codes {
+				if state == code {
+					return trier.ActionRetry
+				}
+			}
+		}
+		return trier.ActionDefault
+	}
+}
+
+// RunTx begins transaction, executes function and commits transaction, retries all of it on transient errors
+// which are identified by ClassifySQLState(DefaultSQLStates...) by default.
+// Transaction is rolled back if function returns error or panics.
+// Errors which the classifier of transaction does not map to ActionRetry are returned without retries,
+// they bypass classifiers of the trier, so ActionStop, ActionAbort and ActionDefault behave the same.
+// Errors mapped to ActionRetry are classified by the trier as usual.
+func RunTx(ctx context.Context, db TxBeginner, tr trier.Trier, fn func(ctx context.Context, tx *sql.Tx) error, opts ...Option) error {
+	o := &options{classifier: ClassifySQLState(DefaultSQLStates...)}
+	for _, f := range opts {
+		f(o)
+	}
+	err := tr.Retry(ctx, func(ctx context.Context) error {
+		err := runTx(ctx, db, o.tx, fn)
+		if err == nil || o.classifier(err) == trier.ActionRetry {
+			return err
+		}
+		return trier.Permanent(err)
+	})
+	var p *trier.PermanentError
+	if errors.As(err, &p) {
+		return p.Err
+	}
+	return err
+}
+
+func runTx(ctx context.Context, db TxBeginner, opts *sql.TxOptions, fn func(ctx context.Context, tx *sql.Tx) error) (err error) {
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if v := recover(); v != nil {
+			tx.Rollback()
+			panic(v)
+		}
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+	if err = fn(ctx, tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
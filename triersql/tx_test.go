@@ -0,0 +1,142 @@
+package triersql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/da440dil/go-trier"
+	"github.com/stretchr/testify/require"
+)
+
+type sqlError string
+
+func (e sqlError) Error() string    { return "sql error " + string(e) }
+func (e sqlError) SQLState() string { return string(e) }
+
+type fakeDriver struct {
+	begins, commits, rollbacks int
+	commitErrs                 []error
+}
+
+func (d *fakeDriver) Connect(context.Context) (driver.Conn, error) { return fakeConn{d}, nil }
+func (d *fakeDriver) Driver() driver.Driver                        { return d }
+func (d *fakeDriver) Open(string) (driver.Conn, error)             { return fakeConn{d}, nil }
+
+type fakeConn struct {
+	d *fakeDriver
+}
+
+func (c fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not implemented") }
+func (c fakeConn) Close() error                        { return nil }
+func (c fakeConn) Begin() (driver.Tx, error) {
+	c.d.begins++
+	return fakeTx{c.d}, nil
+}
+
+type fakeTx struct {
+	d *fakeDriver
+}
+
+func (tx fakeTx) Commit() error {
+	tx.d.commits++
+	if len(tx.d.commitErrs) == 0 {
+		return nil
+	}
+	err := tx.d.commitErrs[0]
+	tx.d.commitErrs = tx.d.commitErrs[1:]
+	return err
+}
+
+func (tx fakeTx) Rollback() error {
+	tx.d.rollbacks++
+	return nil
+}
+
+func newDB(t *testing.T) (*sql.DB, *fakeDriver) {
+	d := &fakeDriver{}
+	db := sql.OpenDB(d)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db, d
+}
+
+func TestRunTx(t *testing.T) {
+	db, d := newDB(t)
+	tr := trier.NewTrier(trier.Constant(time.Millisecond), trier.WithMaxRetries(2))
+	ctx := context.Background()
+
+	n := 0
+	err := RunTx(ctx, db, tr, func(ctx context.Context, tx *sql.Tx) error {
+		n++
+		if n == 1 {
+			return sqlError("40P01")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	require.Equal(t, 2, d.begins)
+	require.Equal(t, 1, d.commits)
+	require.Equal(t, 1, d.rollbacks)
+
+	*d = fakeDriver{commitErrs: []error{sqlError("40001"), sqlError("40001"), sqlError("40001")}}
+	err = RunTx(ctx, db, tr, func(ctx context.Context, tx *sql.Tx) error {
+		return nil
+	})
+	require.True(t, errors.Is(err, trier.ErrExhausted))
+	require.True(t, errors.Is(err, sqlError("40001")))
+	require.Equal(t, 3, d.begins)
+	require.Equal(t, 3, d.commits)
+
+	*d = fakeDriver{}
+	e := errors.New("some error")
+	err = RunTx(ctx, db, tr, func(ctx context.Context, tx *sql.Tx) error {
+		return e
+	})
+	require.Equal(t, e, err)
+	require.Equal(t, 1, d.begins)
+	require.Equal(t, 0, d.commits)
+	require.Equal(t, 1, d.rollbacks)
+}
+
+func TestRunTxWithOptions(t *testing.T) {
+	db, d := newDB(t)
+	tr := trier.NewTrier(trier.Constant(time.Millisecond), trier.WithMaxRetries(2))
+	e := errors.New("some error")
+	n := 0
+	err := RunTx(context.Background(), db, tr, func(ctx context.Context, tx *sql.Tx) error {
+		n++
+		if n == 1 {
+			return e
+		}
+		return nil
+	}, WithClassifier(func(err error) trier.Action {
+		if err == e {
+			return trier.ActionRetry
+		}
+		return trier.ActionDefault
+	}), WithTxOptions(&sql.TxOptions{}))
+	require.NoError(t, err)
+	require.Equal(t, 2, d.begins)
+
+	// the driver does not support read-only transactions
+	err = RunTx(context.Background(), db, tr, func(ctx context.Context, tx *sql.Tx) error {
+		return nil
+	}, WithTxOptions(&sql.TxOptions{ReadOnly: true}))
+	require.Error(t, err)
+}
+
+func TestRunTxPanic(t *testing.T) {
+	db, d := newDB(t)
+	tr := trier.NewTrier(trier.Constant(time.Millisecond))
+	require.PanicsWithValue(t, "some panic", func() {
+		RunTx(context.Background(), db, tr, func(ctx context.Context, tx *sql.Tx) error {
+			panic("some panic")
+		})
+	})
+	require.Equal(t, 1, d.rollbacks)
+}
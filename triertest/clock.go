@@ -0,0 +1,141 @@
+// Package triertest provides utilities for testing code which uses trier.
+package triertest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/da440dil/go-trier"
+)
+
+// Clock is a fake clock which time is advanced manually.
+type Clock struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	now    time.Time
+	auto   bool
+	timers []*timer
+	delays []time.Duration
+}
+
+// NewClock creates new fake clock which time is advanced using Advance.
+func NewClock(now time.Time) *Clock {
+	c := &Clock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// NewAutoClock creates new fake clock which time is advanced by the duration of each timer
+// as soon as the timer is set, so trier never waits.
+func NewAutoClock(now time.Time) *Clock {
+	c := NewClock(now)
+	c.auto = true
+	return c
+}
+
+// Now returns current time of the clock.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTimer creates new timer which fires when the clock is advanced by the duration.
+func (c *Clock) NewTimer(d time.Duration) trier.Timer {
+	t := &timer{c: c, ch: make(chan time.Time, 1)}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.schedule(t, d)
+	return t
+}
+
+// Advance advances time of the clock by the duration, fires expired timers.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.advance(d)
+}
+
+// Delays returns durations of all timers set using the clock.
+func (c *Clock) Delays() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]time.Duration(nil), c.delays...)
+}
+
+// BlockUntil blocks until the number of active timers of the clock equals n.
+func (c *Clock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.timers) != n {
+		c.cond.Wait()
+	}
+}
+
+func (c *Clock) schedule(t *timer, d time.Duration) {
+	t.at = c.now.Add(d)
+	c.delays = append(c.delays, d)
+	c.timers = append(c.timers, t)
+	c.cond.Broadcast()
+	if c.auto {
+		c.advance(d)
+	} else {
+		c.fire()
+	}
+}
+
+func (c *Clock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+	c.fire()
+}
+
+func (c *Clock) fire() {
+	timers := c.timers[:0]
+	for _, t := range c.timers {
+		if t.at.After(c.now) {
+			timers = append(timers, t)
+			continue
+		}
+		select {
+		case t.ch <- c.now:
+		default:
+		}
+	}
+	c.timers = timers
+	c.cond.Broadcast()
+}
+
+func (c *Clock) remove(t *timer) bool {
+	for i, v := range c.timers {
+		if v == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			c.cond.Broadcast()
+			return true
+		}
+	}
+	return false
+}
+
+type timer struct {
+	c  *Clock
+	ch chan time.Time
+	at time.Time
+}
+
+func (t *timer) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *timer) Reset(d time.Duration) bool {
+	t.c.mu.Lock()
+	defer t.c.mu.Unlock()
+	active := t.c.remove(t)
+	t.c.schedule(t, d)
+	return active
+}
+
+func (t *timer) Stop() bool {
+	t.c.mu.Lock()
+	defer t.c.mu.Unlock()
+	return t.c.remove(t)
+}
@@ -0,0 +1,79 @@
+package triertest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/da440dil/go-trier"
+	"github.com/stretchr/testify/require"
+)
+
+var _ trier.Clock = (*Clock)(nil)
+
+func TestClock(t *testing.T) {
+	now := time.Unix(0, 0)
+	c := NewClock(now)
+	require.Equal(t, now, c.Now())
+
+	tm := c.NewTimer(time.Second)
+	c.Advance(time.Millisecond * 999)
+	select {
+	case <-tm.C():
+		t.Fatal("timer fired too early")
+	default:
+	}
+	c.Advance(time.Millisecond)
+	require.Equal(t, now.Add(time.Second), <-tm.C())
+	require.False(t, tm.Stop())
+
+	require.False(t, tm.Reset(time.Second))
+	require.True(t, tm.Reset(time.Second*2))
+	require.True(t, tm.Stop())
+	c.Advance(time.Second * 2)
+	select {
+	case <-tm.C():
+		t.Fatal("stopped timer fired")
+	default:
+	}
+
+	tm = c.NewTimer(0)
+	<-tm.C()
+	require.Equal(t, []time.Duration{time.Second, time.Second, time.Second * 2, 0}, c.Delays())
+}
+
+func TestClockBlockUntil(t *testing.T) {
+	c := NewClock(time.Unix(0, 0))
+	tr := trier.NewTrierWithOptions(trier.Linear(time.Second), trier.WithMaxRetries(2), trier.WithClock(c))
+	ch := make(chan error)
+	go func() {
+		ch <- tr.Run(context.Background(), func(ctx context.Context) (bool, error) {
+			return false, nil
+		})
+	}()
+	c.BlockUntil(1)
+	c.Advance(time.Second)
+	c.BlockUntil(1)
+	c.Advance(time.Second * 2)
+	err := <-ch
+	require.True(t, errors.Is(err, trier.ErrExhausted))
+	var e *trier.ExhaustedError
+	require.True(t, errors.As(err, &e))
+	require.Equal(t, time.Second*3, e.Elapsed)
+	require.Equal(t, []time.Duration{time.Second, time.Second * 2}, c.Delays())
+}
+
+func TestAutoClock(t *testing.T) {
+	c := NewAutoClock(time.Unix(0, 0))
+	tr := trier.NewTrierWithOptions(trier.Exponential(time.Hour), trier.WithMaxRetries(3), trier.WithClock(c))
+	n := 0
+	ok, err := tr.Try(context.Background(), func(ctx context.Context) (bool, error) {
+		n++
+		return n == 4, nil
+	})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []time.Duration{time.Hour, time.Hour * 2, time.Hour * 4}, c.Delays())
+	require.Equal(t, time.Unix(0, 0).Add(time.Hour*7), c.Now())
+}